@@ -1,48 +1,336 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
 	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/file/filehandler"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/http/httphandler"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/socket/sockethandler"
 	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// stdinFilename stands for the standard input instead of a file
+const stdinFilename = "-"
+
+const (
+	csvReportTables = "tables"
+	csvReportEvents = "events"
 )
 
 func main() {
-	args := os.Args
-	if len(args) != 2 {
-		panic("filename must be provided as second argument")
+	format := flag.String("format", formatText, "format of the report: text or json")
+	csvReports := flag.String("csv", "", "comma separated CSV reports to write next to the input file: tables, events")
+	validate := flag.Bool("validate", false, "report every invalid line of the file instead of the working day report")
+	diagnostics := flag.Bool("diagnostics", false, "explain invalid lines with line number, column and violated rule")
+	validateFirst := flag.Bool("validate-first", false, "check the whole file before the text report, so that only the invalid line is printed, if there is one")
+	listen := flag.String("listen", "", "address to accept events live on, the file provides the config only")
+	network := flag.String("network", "tcp", "network of the listen address: tcp or unix")
+	snapshot := flag.String("snapshot", "", "file to keep the state of the live club in and to restore it from on start")
+	journal := flag.String("journal", "", "file to append every accepted line of the live club to and to replay on start")
+	snapshotInterval := flag.Int("snapshot-interval", 100, "number of journal entries between snapshots, when there is a journal")
+	httpAddress := flag.String("http", "", "address to serve the HTTP API on, the file provides the config only")
+	statusAt := flag.String("status-at", "", "print the state of the club at HH:MM, or at YYYY-MM-DD HH:MM for a file with dates, instead of the report")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		panic("filename must be provided as argument")
 	}
 
-	filename := os.Args[1]
+	if *format != formatText && *format != formatJSON {
+		panic("format must be text or json")
+	}
 
-	computerClubConfig := computerclub.Config{}
+	filename := flag.Arg(0)
 
-	invalidLine, err := filehandler.ProcessComputerClubConfig(filename, &computerClubConfig)
+	if *validate {
+		validateFile(filename, *diagnostics)
+		return
+	}
+
+	if *listen != "" {
+		serve(filename, *network, *listen, *snapshot, *journal, *snapshotInterval, *diagnostics)
+		return
+	}
+
+	if *httpAddress != "" {
+		serveHTTP(filename, *httpAddress, *diagnostics)
+		return
+	}
+
+	if *statusAt != "" {
+		printStatus(filename, *statusAt, *diagnostics)
+		return
+	}
+
+	csvReportNames, err := parseCSVReportNames(*csvReports)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	if filename == stdinFilename && len(csvReportNames) > 0 {
+		panic("csv reports can not be written for stdin")
+	}
+
+	if *validateFirst && filename == stdinFilename {
+		panic("stdin can not be checked before the report, as it is read once")
+	}
+
+	if *format == formatText && len(csvReportNames) == 0 {
+		streamReport(filename, *validateFirst, *diagnostics)
+		return
+	}
+
+	input, err := openInput(filename)
+	if err != nil {
+		panic(err.Error())
+	}
+	defer input.Close()
+
+	periodReport, invalidLine, err := filehandler.ReadPeriodReport(input, newEventHandler)
+	if err != nil {
+		printInvalidLine(filename, invalidLine, err, *diagnostics)
+		return
+	}
+
+	err = writeCSVReports(periodReport, filename, csvReportNames)
 	if err != nil {
-		if invalidLine != nil {
-			fmt.Println(*invalidLine)
-		} else {
+		panic(err.Error())
+	}
+
+	if *format == formatJSON {
+		jsonReport, err := periodReport.JSONReport()
+		if err != nil {
 			panic(err.Error())
 		}
+		fmt.Println(string(jsonReport))
 		return
 	}
 
-	computerClubService := computerclub.NewComputerClub(&computerClubConfig)
+	fmt.Print(periodReport.WorkingDayReport())
+}
 
-	eventHandler := eventhandler.NewHandler(computerClubService)
+func newEventHandler(config *computerclub.Config) eventhandler.Handler {
+	return eventhandler.NewHandler(computerclub.NewComputerClub(config))
+}
 
-	fileHandler := filehandler.NewHandler(eventHandler)
+func openInput(filename string) (io.ReadCloser, error) {
+	if filename == stdinFilename {
+		return os.Stdin, nil
+	}
+	return os.Open(filename)
+}
+
+// streamReport prints the lines of the text report as soon as they are known,
+// the lines before an invalid line stay printed. With validateFirst the file is
+// checked beforehand, so that only the invalid line is printed.
+func streamReport(filename string, validateFirst bool, diagnostics bool) {
+	var invalidLine *filehandler.InvalidLine
+
+	if validateFirst {
+		file, err := os.Open(filename)
+		if err != nil {
+			panic(err.Error())
+		}
+		defer file.Close()
 
-	workingDayReport, invalidLine, err := fileHandler.GetWorkingDayReport(filename, computerClubConfig.TablesCount)
+		invalidLine, err = filehandler.StreamValidWorkingDayReport(file, os.Stdout, newEventHandler)
+		if err != nil {
+			printInvalidLine(filename, invalidLine, err, diagnostics)
+		}
+		return
+	}
+
+	input, err := openInput(filename)
+	if err != nil {
+		panic(err.Error())
+	}
+	defer input.Close()
+
+	invalidLine, err = filehandler.StreamWorkingDayReport(input, os.Stdout, newEventHandler)
+	if err != nil {
+		printInvalidLine(filename, invalidLine, err, diagnostics)
+	}
+}
+
+// printStatus prints the state of the club at the status time.
+func printStatus(filename string, statusAt string, diagnostics bool) {
+	input, err := openInput(filename)
+	if err != nil {
+		panic(err.Error())
+	}
+	defer input.Close()
+
+	status, invalidLine, err := filehandler.ReadStatus(input, statusAt, newEventHandler)
 	if err != nil {
-		if invalidLine != nil {
-			fmt.Println(*invalidLine)
-		} else {
+		printInvalidLine(filename, invalidLine, err, diagnostics)
+		return
+	}
+
+	fmt.Print(status.Report())
+}
+
+// serve runs the club configured by the file live on the address.
+func serve(filename string, network string, address string, snapshotFilename string, journalFilename string, snapshotInterval int, diagnostics bool) {
+	if network != "tcp" && network != "unix" {
+		panic("network must be tcp or unix")
+	}
+
+	if snapshotInterval <= 0 {
+		panic("snapshot interval must be positive")
+	}
+
+	computerClubConfig, ok := readConfig(filename, diagnostics)
+	if !ok {
+		return
+	}
+
+	server := sockethandler.NewServer(computerClubConfig, newEventHandler)
+
+	if snapshotFilename != "" {
+		err := server.UseSnapshotFile(snapshotFilename)
+		if err != nil {
 			panic(err.Error())
 		}
+	}
+
+	if journalFilename != "" {
+		err := server.UseJournalFile(journalFilename, snapshotInterval)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+
+	err := server.ListenAndServe(network, address)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// serveHTTP runs the HTTP API of the club configured by the file.
+func serveHTTP(filename string, address string, diagnostics bool) {
+	computerClubConfig, ok := readConfig(filename, diagnostics)
+	if !ok {
+		return
+	}
+
+	httpHandler := httphandler.NewHandler(computerClubConfig, computerclub.NewComputerClub)
+
+	err := http.ListenAndServe(address, httpHandler.Routes())
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// readConfig reads the config of the club from the file. The invalid line is
+// printed, if there is one.
+func readConfig(filename string, diagnostics bool) (*computerclub.Config, bool) {
+	computerClubConfig := computerclub.Config{}
+
+	invalidLine, err := filehandler.ProcessComputerClubConfig(filename, &computerClubConfig)
+	if err != nil {
+		printInvalidLine(filename, invalidLine, err, diagnostics)
+		return nil, false
+	}
+
+	return &computerClubConfig, true
+}
+
+// printInvalidLine prints the first invalid line of the file, or the explanation
+// of it, if diagnostics are asked for.
+func printInvalidLine(filename string, invalidLine *filehandler.InvalidLine, err error, diagnostics bool) {
+	if invalidLine == nil {
+		panic(err.Error())
+	}
+
+	var parseError *filehandler.ParseError
+	if diagnostics && errors.As(err, &parseError) {
+		fmt.Println(parseError.Diagnostic(filename))
 		return
 	}
 
-	fmt.Print(workingDayReport)
+	fmt.Println(*invalidLine)
+}
+
+func parseCSVReportNames(csvReports string) ([]string, error) {
+	if csvReports == "" {
+		return nil, nil
+	}
+
+	csvReportNames := strings.Split(csvReports, ",")
+
+	for _, csvReportName := range csvReportNames {
+		if csvReportName != csvReportTables && csvReportName != csvReportEvents {
+			return nil, fmt.Errorf("unknown csv report: %s", csvReportName)
+		}
+	}
+
+	return csvReportNames, nil
+}
+
+// writeCSVReports writes every CSV report to a file named after the input file,
+// e.g. day.tables.csv for day.txt.
+func writeCSVReports(periodReport *filehandler.PeriodReport, filename string, csvReportNames []string) error {
+	for _, csvReportName := range csvReportNames {
+		var csvReport []byte
+		var err error
+
+		switch csvReportName {
+		case csvReportTables:
+			csvReport, err = periodReport.TablesCSVReport()
+		case csvReportEvents:
+			csvReport, err = periodReport.EventsCSVReport()
+		}
+		if err != nil {
+			return err
+		}
+
+		csvFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + "." + csvReportName + ".csv"
+
+		err = os.WriteFile(csvFilename, csvReport, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateFile prints every invalid line of the file and exits with non-zero
+// code, if there are any.
+func validateFile(filename string, diagnostics bool) {
+	var validationErrors []filehandler.ParseError
+	var err error
+
+	if filename == stdinFilename {
+		validationErrors, err = filehandler.ValidateReader(os.Stdin)
+	} else {
+		validationErrors, err = filehandler.ValidateFile(filename)
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+
+	for _, validationError := range validationErrors {
+		if diagnostics {
+			fmt.Println(validationError.Diagnostic(filename))
+			continue
+		}
+		fmt.Println(validationError.Error())
+	}
+
+	if len(validationErrors) > 0 {
+		os.Exit(1)
+	}
 }
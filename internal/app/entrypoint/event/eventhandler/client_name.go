@@ -0,0 +1,20 @@
+package eventhandler
+
+// IsValidClientName reports whether the name is made of lower case letters,
+// digits, '_' and '-'. The names of the config, e.g. table categories, follow
+// the same rule.
+func IsValidClientName(clientName string) bool {
+	if clientName == "" {
+		return false
+	}
+
+	for i := 0; i < len(clientName); i++ {
+		isLowerCaseLetter := clientName[i] >= 'a' && clientName[i] <= 'z'
+		isDigit := clientName[i] >= '0' && clientName[i] <= '9'
+		if !isLowerCaseLetter && !isDigit && clientName[i] != '_' && clientName[i] != '-' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -7,4 +7,9 @@ type Event struct {
 	Type       uint8
 	ClientName string
 	TableId    int
+
+	// ReservationStart and ReservationEnd bound the window of a reservation
+	// event, they are HH:MM times, that follow the event time
+	ReservationStart time.Time
+	ReservationEnd   time.Time
 }
@@ -10,9 +10,11 @@ import (
 
 var ErrInvalidEventLine = errors.New("invalid event line")
 
+const reservationEventLineLen = 6
+
 func FromEventLine(eventLine string) (*Event, error) {
 	splitEventLine := strings.Split(eventLine, " ")
-	if len(splitEventLine) != 3 && len(splitEventLine) != 4 {
+	if len(splitEventLine) != 3 && len(splitEventLine) != 4 && len(splitEventLine) != reservationEventLineLen {
 		return nil, ErrInvalidEventLine
 	}
 
@@ -27,7 +29,7 @@ func FromEventLine(eventLine string) (*Event, error) {
 	}
 
 	tableId := 0
-	if len(splitEventLine) == 4 {
+	if len(splitEventLine) >= 4 {
 		tableId, err = strconv.Atoi(splitEventLine[3])
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert tableId: %w", err)
@@ -43,5 +45,17 @@ func FromEventLine(eventLine string) (*Event, error) {
 		TableId:    tableId,
 	}
 
+	if len(splitEventLine) == reservationEventLineLen {
+		event.ReservationStart, err = xtime.ParseHoursMinutesFromString(splitEventLine[4])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reservation start: %w", err)
+		}
+
+		event.ReservationEnd, err = xtime.ParseHoursMinutesFromString(splitEventLine[5])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reservation end: %w", err)
+		}
+	}
+
 	return event, nil
 }
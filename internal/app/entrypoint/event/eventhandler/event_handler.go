@@ -3,13 +3,28 @@ package eventhandler
 import (
 	"errors"
 	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"time"
 )
 
 type Handler interface {
 	HandleEvent(event *Event) error
 	OpenComputerClub()
+	OpenComputerClubOn(date time.Time)
+	AdvanceTo(at time.Time)
 	CloseComputerClub()
+	GetOutcomes() []computerclub.Outcome
+	GetOutcomesFrom(index int) []computerclub.Outcome
+	TakeOutcomes() []computerclub.Outcome
 	GetWorkingDayReport() computerclub.WorkingDayReport
+	GetPeriodOutcomes() []computerclub.Outcome
+	GetPeriodReport() computerclub.WorkingDayReport
+	GetOccupancy() []computerclub.TableOccupancy
+	GetClients() []computerclub.Client
+	GetQueue(at time.Time) []computerclub.QueuedClient
+	GetRunningRevenue(at time.Time) int
+	Snapshot() computerclub.Snapshot
+	Restore(snapshot computerclub.Snapshot) error
 }
 
 type handlerImpl struct {
@@ -30,6 +45,16 @@ func (h *handlerImpl) HandleEvent(event *Event) error {
 		return h.handleEventClientWaiting(event)
 	case computerclub.IncomingEventClientLeft:
 		return h.handleEventClientLeft(event)
+	case computerclub.IncomingEventClientReserved:
+		return h.handleEventClientReserved(event)
+	case computerclub.IncomingEventTableOutOfService:
+		return h.handleEventTableOutOfService(event)
+	case computerclub.IncomingEventTableBackInService:
+		return h.handleEventTableBackInService(event)
+	case computerclub.IncomingEventClientPaused:
+		return h.handleEventClientPaused(event)
+	case computerclub.IncomingEventClientResumed:
+		return h.handleEventClientResumed(event)
 	default:
 		return errors.New("invalid event type")
 	}
@@ -39,14 +64,66 @@ func (h *handlerImpl) OpenComputerClub() {
 	h.computerClubService.Open()
 }
 
+func (h *handlerImpl) OpenComputerClubOn(date time.Time) {
+	h.computerClubService.OpenOn(date)
+}
+
+func (h *handlerImpl) AdvanceTo(at time.Time) {
+	h.computerClubService.AdvanceTo(at)
+}
+
 func (h *handlerImpl) CloseComputerClub() {
 	h.computerClubService.Close()
 }
 
+func (h *handlerImpl) GetOutcomes() []computerclub.Outcome {
+	return h.computerClubService.GetOutcomes()
+}
+
+func (h *handlerImpl) GetOutcomesFrom(index int) []computerclub.Outcome {
+	return h.computerClubService.GetOutcomesFrom(index)
+}
+
+func (h *handlerImpl) TakeOutcomes() []computerclub.Outcome {
+	return h.computerClubService.TakeOutcomes()
+}
+
 func (h *handlerImpl) GetWorkingDayReport() computerclub.WorkingDayReport {
 	return h.computerClubService.GetWorkingDayReport()
 }
 
+func (h *handlerImpl) GetPeriodOutcomes() []computerclub.Outcome {
+	return h.computerClubService.GetPeriodOutcomes()
+}
+
+func (h *handlerImpl) GetPeriodReport() computerclub.WorkingDayReport {
+	return h.computerClubService.GetPeriodReport()
+}
+
+func (h *handlerImpl) GetOccupancy() []computerclub.TableOccupancy {
+	return h.computerClubService.GetOccupancy()
+}
+
+func (h *handlerImpl) GetClients() []computerclub.Client {
+	return h.computerClubService.GetClients()
+}
+
+func (h *handlerImpl) GetQueue(at time.Time) []computerclub.QueuedClient {
+	return h.computerClubService.GetQueue(at)
+}
+
+func (h *handlerImpl) GetRunningRevenue(at time.Time) int {
+	return h.computerClubService.GetRunningRevenue(at)
+}
+
+func (h *handlerImpl) Snapshot() computerclub.Snapshot {
+	return h.computerClubService.Snapshot()
+}
+
+func (h *handlerImpl) Restore(snapshot computerclub.Snapshot) error {
+	return h.computerClubService.Restore(snapshot)
+}
+
 func (h *handlerImpl) handleEventClientArrived(event *Event) error {
 	err := h.computerClubService.ProcessEventClientArrived(event.Time, computerclub.ClientName(event.ClientName))
 	if err != nil {
@@ -70,7 +147,8 @@ func (h *handlerImpl) handleEventClientTookPlace(event *Event) error {
 func (h *handlerImpl) handleEventClientWaiting(event *Event) error {
 	err := h.computerClubService.ProcessEventClientWaiting(event.Time, computerclub.ClientName(event.ClientName))
 	if err != nil {
-		if !errors.Is(err, computerclub.ErrICanWaitNoLonger) && !errors.Is(err, computerclub.ErrQueueIsFull) {
+		if !errors.Is(err, computerclub.ErrICanWaitNoLonger) && !errors.Is(err, computerclub.ErrQueueIsFull) &&
+			!errors.Is(err, computerclub.ErrClientIsAlreadyWaiting) {
 			return err
 		}
 	}
@@ -86,3 +164,63 @@ func (h *handlerImpl) handleEventClientLeft(event *Event) error {
 	}
 	return nil
 }
+
+func (h *handlerImpl) handleEventClientReserved(event *Event) error {
+	startTime, endTime := reservationWindow(event)
+
+	err := h.computerClubService.ProcessEventClientReserved(event.Time, computerclub.ClientName(event.ClientName), computerclub.TableId(event.TableId), startTime, endTime)
+	if err != nil {
+		if !errors.Is(err, computerclub.ErrNotOpenYet) && !errors.Is(err, computerclub.ErrPlaceIsBusy) &&
+			!errors.Is(err, computerclub.ErrInvalidReservationTime) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *handlerImpl) handleEventTableOutOfService(event *Event) error {
+	err := h.computerClubService.ProcessEventTableOutOfService(event.Time, computerclub.StaffName(event.ClientName), computerclub.TableId(event.TableId))
+	if err != nil {
+		if !errors.Is(err, computerclub.ErrTableIsOutOfService) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *handlerImpl) handleEventTableBackInService(event *Event) error {
+	err := h.computerClubService.ProcessEventTableBackInService(event.Time, computerclub.StaffName(event.ClientName), computerclub.TableId(event.TableId))
+	if err != nil {
+		if !errors.Is(err, computerclub.ErrTableIsInService) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *handlerImpl) handleEventClientPaused(event *Event) error {
+	err := h.computerClubService.ProcessEventClientPaused(event.Time, computerclub.ClientName(event.ClientName))
+	if err != nil {
+		if !errors.Is(err, computerclub.ErrClientUnknown) && !errors.Is(err, computerclub.ErrClientIsNotSeated) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *handlerImpl) handleEventClientResumed(event *Event) error {
+	err := h.computerClubService.ProcessEventClientResumed(event.Time, computerclub.ClientName(event.ClientName))
+	if err != nil {
+		if !errors.Is(err, computerclub.ErrClientUnknown) && !errors.Is(err, computerclub.ErrClientIsNotPaused) {
+			return err
+		}
+	}
+	return nil
+}
+
+// reservationWindow places the window of the reservation on the time line of
+// the event, so the window may continue after midnight.
+func reservationWindow(event *Event) (time.Time, time.Time) {
+	startTime := xtime.NotBefore(event.Time, event.ReservationStart)
+	return startTime, xtime.NotBefore(startTime, event.ReservationEnd)
+}
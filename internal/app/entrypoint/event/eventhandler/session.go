@@ -0,0 +1,121 @@
+package eventhandler
+
+import (
+	"errors"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"time"
+)
+
+var (
+	ErrClubIsNotOpen     = errors.New("club is not open")
+	ErrClubIsAlreadyOpen = errors.New("club is already open")
+	ErrEventSequence     = errors.New("event is earlier than the previous one")
+)
+
+// Session keeps the working day of the club run live: whether the club is
+// open, the time of the last event and the number of outcomes already sent.
+// It places the HH:MM times of events on the working day as the file does.
+type Session struct {
+	config *computerclub.Config
+
+	isOpen bool
+	// writtenOutcomes is the number of outcomes of the day already sent
+	writtenOutcomes int
+	hasEvents       bool
+	lastEventTime   time.Time
+}
+
+func NewSession(config *computerclub.Config) *Session {
+	return &Session{config: config}
+}
+
+// Open starts a new working day.
+func (s *Session) Open() error {
+	if s.isOpen {
+		return ErrClubIsAlreadyOpen
+	}
+
+	s.isOpen = true
+	s.writtenOutcomes = 0
+	s.hasEvents = false
+
+	return nil
+}
+
+func (s *Session) Close() error {
+	if !s.isOpen {
+		return ErrClubIsNotOpen
+	}
+
+	s.isOpen = false
+
+	return nil
+}
+
+func (s *Session) IsOpen() bool {
+	return s.isOpen
+}
+
+// EventTime places the HH:MM time of the event on the working day and checks,
+// that the club is open and the event is not earlier than the previous one.
+// The times of a club, that is open past midnight, up to the closing time
+// belong to the next day.
+func (s *Session) EventTime(clock time.Time) (time.Time, error) {
+	if !s.isOpen {
+		return time.Time{}, ErrClubIsNotOpen
+	}
+
+	eventTime := clock
+	if s.config.IsAfterMidnight(clock) {
+		eventTime = eventTime.Add(24 * time.Hour)
+	}
+
+	if s.hasEvents && eventTime.Before(s.lastEventTime) {
+		return time.Time{}, ErrEventSequence
+	}
+
+	return eventTime, nil
+}
+
+// AddEvent makes the event the last one of the working day.
+func (s *Session) AddEvent(eventTime time.Time) {
+	s.hasEvents = true
+	s.lastEventTime = eventTime
+}
+
+// LastEventTime returns the time of the last event, it is zero, if there are
+// no events yet.
+func (s *Session) LastEventTime() time.Time {
+	if !s.hasEvents {
+		return time.Time{}
+	}
+	return s.lastEventTime
+}
+
+// TakeOutcomes returns the outcomes of the working day, that are not sent yet.
+func (s *Session) TakeOutcomes(getOutcomesFrom func(index int) []computerclub.Outcome) []computerclub.Outcome {
+	outcomes := getOutcomesFrom(s.writtenOutcomes)
+
+	s.writtenOutcomes += len(outcomes)
+
+	return outcomes
+}
+
+// Restore continues the working day of the outcomes, all of them are
+// considered sent.
+func (s *Session) Restore(outcomes []computerclub.Outcome) {
+	s.writtenOutcomes = len(outcomes)
+	s.isOpen = false
+	s.hasEvents = false
+
+	for _, outcome := range outcomes {
+		switch outcome.Kind {
+		case computerclub.OutcomeKindOpening:
+			s.isOpen = true
+		case computerclub.OutcomeKindClosing:
+			s.isOpen = false
+		case computerclub.OutcomeKindIncomingEvent:
+			s.AddEvent(outcome.Time)
+		}
+	}
+}
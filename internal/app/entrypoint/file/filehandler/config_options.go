@@ -0,0 +1,404 @@
+package filehandler
+
+import (
+	"errors"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFormatBillingPolicy = errors.New("invalid format of billing policy")
+	ErrInvalidFormatTariff        = errors.New("invalid format of tariff")
+	ErrInvalidFormatTableCategory = errors.New("invalid format of table category")
+	ErrInvalidFormatPause         = errors.New("invalid format of pause")
+	ErrInvalidFormatQueuePolicy   = errors.New("invalid format of queue policy")
+	ErrInvalidFormatMembership    = errors.New("invalid format of membership")
+	ErrInvalidFormatQueueSize     = errors.New("invalid format of queue size")
+	ErrInvalidFormatOverflow      = errors.New("invalid format of overflow policy")
+	ErrInvalidFormatMaxWait       = errors.New("invalid format of max wait")
+	ErrInvalidFormatAnalytics     = errors.New("invalid format of analytics")
+)
+
+const (
+	billingPolicyHourly    = "hourly"
+	billingPolicyPerMinute = "minute"
+	billingPolicyBlock     = "block"
+	billingPolicyGrace     = "grace"
+)
+
+const maxGracePeriodMinutes = 59
+
+const (
+	queuePolicyFIFO     = "fifo"
+	queuePolicyPriority = "priority"
+	queuePolicyAging    = "aging"
+)
+
+const (
+	minTariffArgsLen = 3
+	maxTariffArgsLen = 4
+)
+
+const minTableCategoryArgsLen = 3
+
+const pauseArgsLen = 1
+
+const minMembershipArgsLen = 2
+
+const (
+	queueSizeArgsLen = 1
+	overflowArgsLen  = 1
+	maxWaitArgsLen   = 1
+)
+
+// overflowPolicies hold the overflow policies by their names.
+var overflowPolicies = map[string]computerclub.OverflowPolicy{
+	"turn-away":    computerclub.OverflowPolicyTurnAway,
+	"waiting-area": computerclub.OverflowPolicyWaitingArea,
+	"partner-club": computerclub.OverflowPolicyPartnerClub,
+}
+
+// configOptionParser parses the arguments of an optional config line.
+type configOptionParser func(args []string, config *computerclub.Config) error
+
+// configOptionParsers holds the optional config lines, that may follow the
+// price per hour line, by their keyword.
+var configOptionParsers = map[string]configOptionParser{
+	"billing":    parseBillingPolicyOption,
+	"tariff":     parseTariffOption,
+	"category":   parseTableCategoryOption,
+	"pause":      parsePauseOption,
+	"queue":      parseQueuePolicyOption,
+	"membership": parseMembershipOption,
+	"queue-size": parseQueueSizeOption,
+	"overflow":   parseOverflowPolicyOption,
+	"max-wait":   parseMaxWaitOption,
+	"analytics":  parseAnalyticsOption,
+}
+
+func isConfigOptionLine(line string) bool {
+	keyword, _, _ := strings.Cut(line, " ")
+	_, ok := configOptionParsers[keyword]
+	return ok
+}
+
+func parseConfigOptionLine(line string, config *computerclub.Config) error {
+	splitLine := strings.Split(line, " ")
+	parseOption := configOptionParsers[splitLine[0]]
+	return parseOption(splitLine[1:], config)
+}
+
+// parseBillingPolicyOption parses "billing hourly", "billing minute",
+// "billing block <minutes>" and "billing grace <minutes>".
+func parseBillingPolicyOption(args []string, config *computerclub.Config) error {
+	if len(args) == 0 {
+		return ErrInvalidFormatBillingPolicy
+	}
+
+	switch args[0] {
+	case billingPolicyHourly, billingPolicyPerMinute:
+		if len(args) != 1 {
+			return ErrInvalidFormatBillingPolicy
+		}
+		if args[0] == billingPolicyHourly {
+			config.BillingPolicy = computerclub.NewHourlyBillingPolicy()
+		} else {
+			config.BillingPolicy = computerclub.NewPerMinuteBillingPolicy()
+		}
+	case billingPolicyBlock:
+		blockSize, err := parseBillingPolicyMinutes(args)
+		if err != nil || blockSize == 0 {
+			return ErrInvalidFormatBillingPolicy
+		}
+		config.BillingPolicy = computerclub.NewBlockBillingPolicy(blockSize)
+	case billingPolicyGrace:
+		gracePeriod, err := parseBillingPolicyMinutes(args)
+		if err != nil || gracePeriod > maxGracePeriodMinutes*time.Minute {
+			return ErrInvalidFormatBillingPolicy
+		}
+		config.BillingPolicy = computerclub.NewGraceBillingPolicy(gracePeriod)
+	default:
+		return ErrInvalidFormatBillingPolicy
+	}
+
+	return nil
+}
+
+func parseBillingPolicyMinutes(args []string) (time.Duration, error) {
+	if len(args) != 2 {
+		return 0, ErrInvalidFormatBillingPolicy
+	}
+
+	minutes, err := strconv.Atoi(args[1])
+	if err != nil || minutes < 0 {
+		return 0, ErrInvalidFormatBillingPolicy
+	}
+
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// parseTariffOption parses "tariff <start HH:MM> <end HH:MM> <price per hour> [category]".
+func parseTariffOption(args []string, config *computerclub.Config) error {
+	if len(args) < minTariffArgsLen || len(args) > maxTariffArgsLen {
+		return ErrInvalidFormatTariff
+	}
+
+	startTime, err := parseTime(args[0])
+	if err != nil {
+		return ErrInvalidFormatTariff
+	}
+
+	endTime, err := parseTime(args[1])
+	if err != nil {
+		return ErrInvalidFormatTariff
+	}
+
+	if startTime.Equal(endTime) {
+		return ErrInvalidFormatTariff
+	}
+
+	pricePerHour, err := strconv.Atoi(args[2])
+	if err != nil || pricePerHour < 0 {
+		return ErrInvalidFormatTariff
+	}
+
+	category := computerclub.StandardTableCategory
+	if len(args) == maxTariffArgsLen {
+		category = args[3]
+		if category != computerclub.StandardTableCategory && !hasTableCategory(config, category) {
+			return ErrInvalidFormatTariff
+		}
+	}
+
+	tariff := computerclub.Tariff{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		PricePerHour: pricePerHour,
+		Category:     category,
+	}
+
+	for _, configTariff := range config.Tariffs {
+		if configTariff.Category == tariff.Category && tariffsOverlap(configTariff, tariff) {
+			return ErrInvalidFormatTariff
+		}
+	}
+
+	config.Tariffs = append(config.Tariffs, tariff)
+
+	return nil
+}
+
+func tariffsOverlap(tariff1 computerclub.Tariff, tariff2 computerclub.Tariff) bool {
+	return tariffContains(tariff1, tariff2.StartTime) || tariffContains(tariff2, tariff1.StartTime)
+}
+
+func tariffContains(tariff computerclub.Tariff, t time.Time) bool {
+	if tariff.StartTime.Before(tariff.EndTime) {
+		return !t.Before(tariff.StartTime) && t.Before(tariff.EndTime)
+	}
+	return !t.Before(tariff.StartTime) || t.Before(tariff.EndTime)
+}
+
+// parseTableCategoryOption parses "category <name> <price per hour> <table id>...".
+func parseTableCategoryOption(args []string, config *computerclub.Config) error {
+	if len(args) < minTableCategoryArgsLen {
+		return ErrInvalidFormatTableCategory
+	}
+
+	name := args[0]
+	if !isValidTableCategoryName(name) || hasTableCategory(config, name) {
+		return ErrInvalidFormatTableCategory
+	}
+
+	pricePerHour, err := strconv.Atoi(args[1])
+	if err != nil || pricePerHour < 0 {
+		return ErrInvalidFormatTableCategory
+	}
+
+	category := computerclub.TableCategory{
+		Name:         name,
+		PricePerHour: pricePerHour,
+	}
+
+	for _, strTableId := range args[2:] {
+		tableId, err := strconv.Atoi(strTableId)
+		if err != nil || tableId < minTablesCount || tableId > config.TablesCount {
+			return ErrInvalidFormatTableCategory
+		}
+
+		if hasCategorizedTable(config, computerclub.TableId(tableId)) || slices.Contains(category.TableIds, computerclub.TableId(tableId)) {
+			return ErrInvalidFormatTableCategory
+		}
+
+		category.TableIds = append(category.TableIds, computerclub.TableId(tableId))
+	}
+
+	config.Categories = append(config.Categories, category)
+
+	return nil
+}
+
+func isValidTableCategoryName(name string) bool {
+	return name != computerclub.StandardTableCategory && eventhandler.IsValidClientName(name)
+}
+
+// parsePauseOption parses "pause <max minutes>".
+func parsePauseOption(args []string, config *computerclub.Config) error {
+	if len(args) != pauseArgsLen {
+		return ErrInvalidFormatPause
+	}
+
+	minutes, err := strconv.Atoi(args[0])
+	if err != nil || minutes <= 0 {
+		return ErrInvalidFormatPause
+	}
+
+	config.MaxPauseDuration = time.Duration(minutes) * time.Minute
+
+	return nil
+}
+
+// parseQueuePolicyOption parses "queue fifo", "queue priority" and
+// "queue aging <minutes>".
+func parseQueuePolicyOption(args []string, config *computerclub.Config) error {
+	if len(args) == 0 {
+		return ErrInvalidFormatQueuePolicy
+	}
+
+	switch args[0] {
+	case queuePolicyFIFO, queuePolicyPriority:
+		if len(args) != 1 {
+			return ErrInvalidFormatQueuePolicy
+		}
+		if args[0] == queuePolicyFIFO {
+			config.QueuePolicy = computerclub.NewFIFOQueuePolicy()
+		} else {
+			config.QueuePolicy = computerclub.NewPriorityQueuePolicy()
+		}
+	case queuePolicyAging:
+		if len(args) != 2 {
+			return ErrInvalidFormatQueuePolicy
+		}
+
+		minutes, err := strconv.Atoi(args[1])
+		if err != nil || minutes <= 0 {
+			return ErrInvalidFormatQueuePolicy
+		}
+
+		config.QueuePolicy = computerclub.NewAgingQueuePolicy(time.Duration(minutes) * time.Minute)
+	default:
+		return ErrInvalidFormatQueuePolicy
+	}
+
+	return nil
+}
+
+// parseMembershipOption parses "membership <level> <client name>...".
+func parseMembershipOption(args []string, config *computerclub.Config) error {
+	if len(args) < minMembershipArgsLen {
+		return ErrInvalidFormatMembership
+	}
+
+	level, err := strconv.Atoi(args[0])
+	if err != nil || level <= 0 {
+		return ErrInvalidFormatMembership
+	}
+
+	if config.Memberships == nil {
+		config.Memberships = make(map[computerclub.ClientName]int)
+	}
+
+	for _, strClientName := range args[1:] {
+		clientName := computerclub.ClientName(strClientName)
+
+		if _, ok := config.Memberships[clientName]; ok || !eventhandler.IsValidClientName(strClientName) {
+			return ErrInvalidFormatMembership
+		}
+
+		config.Memberships[clientName] = level
+	}
+
+	return nil
+}
+
+// parseQueueSizeOption parses "queue-size <clients>".
+func parseQueueSizeOption(args []string, config *computerclub.Config) error {
+	if len(args) != queueSizeArgsLen {
+		return ErrInvalidFormatQueueSize
+	}
+
+	queueSize, err := strconv.Atoi(args[0])
+	if err != nil || queueSize <= 0 {
+		return ErrInvalidFormatQueueSize
+	}
+
+	config.QueueSize = queueSize
+
+	return nil
+}
+
+// parseOverflowPolicyOption parses "overflow turn-away", "overflow waiting-area"
+// and "overflow partner-club".
+func parseOverflowPolicyOption(args []string, config *computerclub.Config) error {
+	if len(args) != overflowArgsLen {
+		return ErrInvalidFormatOverflow
+	}
+
+	overflowPolicy, ok := overflowPolicies[args[0]]
+	if !ok {
+		return ErrInvalidFormatOverflow
+	}
+
+	config.OverflowPolicy = overflowPolicy
+
+	return nil
+}
+
+// parseMaxWaitOption parses "max-wait <minutes>".
+func parseMaxWaitOption(args []string, config *computerclub.Config) error {
+	if len(args) != maxWaitArgsLen {
+		return ErrInvalidFormatMaxWait
+	}
+
+	minutes, err := strconv.Atoi(args[0])
+	if err != nil || minutes <= 0 {
+		return ErrInvalidFormatMaxWait
+	}
+
+	config.MaxWaitDuration = time.Duration(minutes) * time.Minute
+
+	return nil
+}
+
+// parseAnalyticsOption parses "analytics".
+func parseAnalyticsOption(args []string, config *computerclub.Config) error {
+	if len(args) != 0 {
+		return ErrInvalidFormatAnalytics
+	}
+
+	config.QueueAnalytics = true
+
+	return nil
+}
+
+func hasTableCategory(config *computerclub.Config, name string) bool {
+	for _, category := range config.Categories {
+		if category.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCategorizedTable(config *computerclub.Config, tableId computerclub.TableId) bool {
+	for _, category := range config.Categories {
+		if slices.Contains(category.TableIds, tableId) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package filehandler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"strconv"
+)
+
+var (
+	tablesCSVHeader = []string{"date", "table", "category", "revenue", "usage_minutes"}
+	eventsCSVHeader = []string{"date", "time", "direction", "id", "client", "table", "error"}
+)
+
+// TablesCSVReport renders the summary of every table of every working day in
+// the CSV format. The date column is empty for a file without dates.
+func (p *PeriodReport) TablesCSVReport() ([]byte, error) {
+	records := [][]string{tablesCSVHeader}
+
+	for _, day := range p.Days {
+		strDate := formatCSVDate(day)
+
+		for _, outcome := range day.Outcomes {
+			if outcome.Kind != computerclub.OutcomeKindTableSummary {
+				continue
+			}
+
+			records = append(records, []string{
+				strDate,
+				strconv.Itoa(int(outcome.TableId)),
+				outcome.Category,
+				strconv.Itoa(outcome.Profit),
+				strconv.Itoa(int(outcome.UsageTime.Minutes())),
+			})
+		}
+	}
+
+	return writeCSV(records)
+}
+
+// EventsCSVReport renders the incoming and outgoing events of every working day
+// in the CSV format. The date column holds the date of the working day.
+func (p *PeriodReport) EventsCSVReport() ([]byte, error) {
+	records := [][]string{eventsCSVHeader}
+
+	for _, day := range p.Days {
+		strDate := formatCSVDate(day)
+
+		for _, outcome := range day.Outcomes {
+			if outcome.Kind != computerclub.OutcomeKindIncomingEvent && outcome.Kind != computerclub.OutcomeKindOutgoingEvent {
+				continue
+			}
+
+			var strTableId string
+			if outcome.TableId != 0 {
+				strTableId = strconv.Itoa(int(outcome.TableId))
+			}
+
+			var errorName string
+			if outcome.Err != nil {
+				errorName = outcome.Err.Error()
+			}
+
+			records = append(records, []string{
+				strDate,
+				outcome.Time.Format(layoutHoursMinutes),
+				eventDirection(outcome),
+				strconv.Itoa(int(outcome.EventType)),
+				string(outcome.ClientName),
+				strTableId,
+				errorName,
+			})
+		}
+	}
+
+	return writeCSV(records)
+}
+
+func formatCSVDate(day DayReport) string {
+	if day.Date.IsZero() {
+		return ""
+	}
+	return xtime.FormatDate(day.Date)
+}
+
+func writeCSV(records [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := csv.NewWriter(&buf)
+
+	err := writer.WriteAll(records)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+package filehandler
+
+import (
+	"fmt"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"testing"
+	"time"
+)
+
+func TestCSVReports(t *testing.T) {
+	const layout = "15:04"
+
+	eventTime, _ := time.Parse(layout, "09:30")
+	date := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	periodReport := PeriodReport{
+		Days: []DayReport{
+			{
+				Date: date,
+				Outcomes: []computerclub.Outcome{
+					{Kind: computerclub.OutcomeKindIncomingEvent, Time: eventTime, EventType: computerclub.IncomingEventClientArrived, ClientName: "client1"},
+					{Kind: computerclub.OutcomeKindOutgoingEvent, Time: eventTime, EventType: computerclub.OutgoingEventError, Err: computerclub.ErrICanWaitNoLonger},
+					{Kind: computerclub.OutcomeKindOutgoingEvent, Time: eventTime, EventType: computerclub.OutgoingEventClientTookPlace, ClientName: "client2", TableId: 2},
+					{Kind: computerclub.OutcomeKindTableSummary, TableId: 1, Category: "vip", Profit: 30, UsageTime: 90 * time.Minute},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		csvReport         func() ([]byte, error)
+		expectedCSVReport string
+	}{
+		{
+			name:      "tables",
+			csvReport: periodReport.TablesCSVReport,
+			expectedCSVReport: "date,table,category,revenue,usage_minutes\n" +
+				"2024-05-01,1,vip,30,90\n",
+		},
+		{
+			name:      "events",
+			csvReport: periodReport.EventsCSVReport,
+			expectedCSVReport: "date,time,direction,id,client,table,error\n" +
+				"2024-05-01,09:30,incoming,1,client1,,\n" +
+				"2024-05-01,09:30,outgoing,13,,,ICanWaitNoLonger!\n" +
+				"2024-05-01,09:30,outgoing,12,client2,2,\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			csvReport, err := testCase.csvReport()
+			if err != nil {
+				t.Fatalf("TestCSVReports: %s", err.Error())
+			}
+
+			if string(csvReport) != testCase.expectedCSVReport {
+				err = fmt.Errorf("invalid csv report: expected: '%v', got: '%v'", testCase.expectedCSVReport, string(csvReport))
+				t.Fatalf("TestCSVReports: %v", err)
+			}
+		})
+	}
+}
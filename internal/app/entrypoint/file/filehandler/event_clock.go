@@ -0,0 +1,95 @@
+package filehandler
+
+import (
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"time"
+)
+
+// eventClock places the HH:MM times of events on the working day. The times of
+// a club, that is open past midnight, up to the closing time belong to the next
+// day.
+type eventClock struct {
+	config *computerclub.Config
+
+	// day is the date of the current working day, dated is set once the day
+	// was given in the file
+	day     time.Time
+	dated   bool
+	nextDay bool
+
+	lastEventTime time.Time
+}
+
+func newEventClock(config *computerclub.Config) *eventClock {
+	return &eventClock{
+		config: config,
+		day:    xtime.StartOfDay(config.OpeningTime),
+	}
+}
+
+// startDay moves the clock to the working day on the given date.
+func (c *eventClock) startDay(date time.Time) error {
+	if c.dated && !date.After(c.day) {
+		return ErrInvalidFormatEventSequence
+	}
+
+	c.day = xtime.StartOfDay(date)
+	c.dated = true
+	c.nextDay = false
+
+	return nil
+}
+
+// moveTo moves the clock to the working day of the event at the given date and
+// time. It reports whether a new working day has started.
+func (c *eventClock) moveTo(eventTime time.Time) (bool, error) {
+	workingDay := c.workingDayOf(eventTime)
+
+	dayStarted := false
+	if !c.dated || !workingDay.Equal(c.day) {
+		err := c.startDay(workingDay)
+		if err != nil {
+			return false, err
+		}
+		dayStarted = true
+	}
+
+	c.nextDay = xtime.StartOfDay(eventTime).After(c.day)
+
+	return dayStarted, nil
+}
+
+// workingDayOf returns the date of the working day, that the event at the given
+// date and time belongs to.
+func (c *eventClock) workingDayOf(eventTime time.Time) time.Time {
+	day := xtime.StartOfDay(eventTime)
+
+	if c.config.IsAfterMidnight(eventTime) {
+		return day.AddDate(0, 0, -1)
+	}
+
+	return day
+}
+
+// tick moves the clock to the time of the next event of the working day.
+func (c *eventClock) tick(eventTime time.Time) error {
+	eventTime = xtime.OnDate(c.day, eventTime)
+
+	// the date of a dated event says whether it is after midnight
+	if c.nextDay || !c.dated && c.config.IsAfterMidnight(eventTime) {
+		eventTime = eventTime.Add(24 * time.Hour)
+	}
+
+	if c.isBeforeLastEvent(eventTime) {
+		return ErrInvalidFormatEventSequence
+	}
+
+	c.lastEventTime = eventTime
+
+	return nil
+}
+
+func (c *eventClock) isBeforeLastEvent(eventTime time.Time) bool {
+	return !c.lastEventTime.IsZero() && eventTime.Before(c.lastEventTime)
+}
@@ -0,0 +1,89 @@
+package filehandler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventClockFirstEventAfterMidnight(t *testing.T) {
+	content := "1\n" +
+		"18:00 04:00\n" +
+		"10\n" +
+		"01:00 1 alice\n" +
+		"01:10 2 alice 1\n" +
+		"03:10 4 alice\n"
+
+	var report strings.Builder
+
+	_, err := StreamWorkingDayReport(strings.NewReader(content), &report, newTestEventHandler)
+	if err != nil {
+		t.Fatalf("TestEventClockFirstEventAfterMidnight: %s", err.Error())
+	}
+
+	expectedReport := "18:00\n" +
+		"01:00 1 alice\n" +
+		"01:10 2 alice 1\n" +
+		"03:10 4 alice\n" +
+		"04:00\n" +
+		"1 20 02:00\n"
+
+	if report.String() != expectedReport {
+		err = fmt.Errorf("expected report: '%s', got: '%s'", expectedReport, report.String())
+		t.Fatalf("TestEventClockFirstEventAfterMidnight: %s", err.Error())
+	}
+}
+
+func TestEventClockEarlierEvent(t *testing.T) {
+	eventClock := newEventClock(newTestOvernightConfig())
+
+	for _, strEventTime := range []string{"17:50", "21:00"} {
+		err := eventClock.tick(mustParseTime(t, strEventTime))
+		if err != nil {
+			t.Fatalf("TestEventClockEarlierEvent: %s", err.Error())
+		}
+	}
+
+	// the time is not moved to the next day, as it is after the closing time
+	err := eventClock.tick(mustParseTime(t, "20:00"))
+	if !errors.Is(err, ErrInvalidFormatEventSequence) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrInvalidFormatEventSequence, err)
+		t.Fatalf("TestEventClockEarlierEvent: %s", err.Error())
+	}
+
+	err = eventClock.tick(mustParseTime(t, "01:00"))
+	if err != nil {
+		t.Fatalf("TestEventClockEarlierEvent: %s", err.Error())
+	}
+
+	// the time after the closing time can not follow the events after midnight
+	err = eventClock.tick(mustParseTime(t, "05:00"))
+	if !errors.Is(err, ErrInvalidFormatEventSequence) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrInvalidFormatEventSequence, err)
+		t.Fatalf("TestEventClockEarlierEvent: %s", err.Error())
+	}
+}
+
+func newTestOvernightConfig() *computerclub.Config {
+	openingTime, _ := xtime.ParseHoursMinutesFromString("18:00")
+	closingTime, _ := xtime.ParseHoursMinutesFromString("04:00")
+
+	return &computerclub.Config{
+		TablesCount:  1,
+		OpeningTime:  openingTime,
+		ClosingTime:  closingTime,
+		PricePerHour: 10,
+	}
+}
+
+func mustParseTime(t *testing.T, strTime string) time.Time {
+	parsedTime, err := xtime.ParseHoursMinutesFromString(strTime)
+	if err != nil {
+		t.Fatalf("mustParseTime: %s", err.Error())
+	}
+	return parsedTime
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
 	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
 	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -19,25 +20,44 @@ var (
 	ErrInvalidFormatClientName    = errors.New("invalid format of client name")
 	ErrInvalidFormatTableNumber   = errors.New("invalid format of table number")
 	ErrInvalidFormatEvent         = errors.New("invalid format of event")
+	ErrInvalidFormatEventTime     = errors.New("invalid format of event time")
 	ErrInvalidFormatEventSequence = errors.New("invalid format of event sequence")
 	ErrInvalidFormatFile          = errors.New("invalid format of file")
+	ErrInvalidFormatDate          = errors.New("invalid format of date")
+	ErrInvalidFormatReservation   = errors.New("invalid format of reservation")
 )
 
 const (
 	minFileLinesCount    = 4
-	configLinesCount     = 3
 	openingHoursSplitLen = 2
 
-	minSplitEventLineLen = 3
-	maxSplitEventLineLen = 4
+	minSplitEventLineLen         = 3
+	maxSplitEventLineLen         = 4
+	reservationSplitEventLineLen = 6
 )
 
 const minTablesCount = 1
 
+const dateLayout = "YYYY-MM-DD"
+
+// dateFieldOffset is the number of fields preceding the event in a line
+// starting with a date.
+const dateFieldOffset = 1
+
 type InvalidLine string
 
 type Handler struct {
 	eventHandler eventhandler.Handler
+
+	// reportWriter receives the lines of the text report as they happen, the
+	// period report and the outcomes written are not kept then
+	reportWriter io.Writer
+
+	// statusTime stops the events at the moment the status is asked for, it is
+	// zero, when all events are handled. statusDate is zero for a file without
+	// dates.
+	statusTime time.Time
+	statusDate time.Time
 }
 
 func ProcessComputerClubConfig(filename string, config *computerclub.Config) (*InvalidLine, error) {
@@ -56,12 +76,12 @@ func ProcessComputerClubConfig(filename string, config *computerclub.Config) (*I
 		return nil, ErrInvalidFormatFile
 	}
 
-	scanner := bufio.NewScanner(file)
+	scanner := newLineScanner(bufio.NewScanner(file))
 
 	return processConfigLines(scanner, config)
 }
 
-func processConfigLines(scanner *bufio.Scanner, config *computerclub.Config) (*InvalidLine, error) {
+func processConfigLines(scanner *lineScanner, config *computerclub.Config) (*InvalidLine, error) {
 	invalidLine, err := scanTablesCountLine(scanner, config)
 	if err != nil {
 		return invalidLine, err
@@ -77,10 +97,15 @@ func processConfigLines(scanner *bufio.Scanner, config *computerclub.Config) (*I
 		return invalidLine, err
 	}
 
+	invalidLine, err = scanConfigOptionLines(scanner, config)
+	if err != nil {
+		return invalidLine, err
+	}
+
 	return nil, nil
 }
 
-func scanTablesCountLine(scanner *bufio.Scanner, config *computerclub.Config) (*InvalidLine, error) {
+func scanTablesCountLine(scanner *lineScanner, config *computerclub.Config) (*InvalidLine, error) {
 	var invalidLine InvalidLine
 
 	scanner.Scan()
@@ -89,7 +114,7 @@ func scanTablesCountLine(scanner *bufio.Scanner, config *computerclub.Config) (*
 	tablesCount, err := parseTablesCountLine(tablesCountLine)
 	if err != nil {
 		invalidLine = InvalidLine(tablesCountLine)
-		return &invalidLine, ErrInvalidFormatTablesCount
+		return &invalidLine, newParseError(scanner.LineNumber(), tablesCountLine, ErrInvalidFormatTablesCount, 0)
 	}
 
 	config.TablesCount = tablesCount
@@ -97,7 +122,7 @@ func scanTablesCountLine(scanner *bufio.Scanner, config *computerclub.Config) (*
 	return nil, nil
 }
 
-func scanOpeningHoursLine(scanner *bufio.Scanner, config *computerclub.Config) (*InvalidLine, error) {
+func scanOpeningHoursLine(scanner *lineScanner, config *computerclub.Config) (*InvalidLine, error) {
 	var invalidLine InvalidLine
 
 	scanner.Scan()
@@ -106,7 +131,7 @@ func scanOpeningHoursLine(scanner *bufio.Scanner, config *computerclub.Config) (
 	openingTime, closingTime, err := parseOpeningHoursLine(openingHoursLine)
 	if err != nil {
 		invalidLine = InvalidLine(openingHoursLine)
-		return &invalidLine, ErrInvalidFormatOpeningHours
+		return &invalidLine, newParseError(scanner.LineNumber(), openingHoursLine, ErrInvalidFormatOpeningHours, 0)
 	}
 
 	config.OpeningTime = openingTime
@@ -115,7 +140,7 @@ func scanOpeningHoursLine(scanner *bufio.Scanner, config *computerclub.Config) (
 	return nil, nil
 }
 
-func scanPricePerHourLine(scanner *bufio.Scanner, config *computerclub.Config) (*InvalidLine, error) {
+func scanPricePerHourLine(scanner *lineScanner, config *computerclub.Config) (*InvalidLine, error) {
 	var invalidLine InvalidLine
 
 	scanner.Scan()
@@ -124,12 +149,12 @@ func scanPricePerHourLine(scanner *bufio.Scanner, config *computerclub.Config) (
 	pricePerHour, err := strconv.Atoi(pricePerHourLine)
 	if err != nil {
 		invalidLine = InvalidLine(pricePerHourLine)
-		return &invalidLine, ErrInvalidFormatPricePerHour
+		return &invalidLine, newParseError(scanner.LineNumber(), pricePerHourLine, ErrInvalidFormatPricePerHour, 0)
 	}
 
 	if pricePerHour < 0 {
 		invalidLine = InvalidLine(pricePerHourLine)
-		return &invalidLine, ErrInvalidFormatPricePerHour
+		return &invalidLine, newParseError(scanner.LineNumber(), pricePerHourLine, ErrInvalidFormatPricePerHour, 0)
 	}
 
 	config.PricePerHour = pricePerHour
@@ -137,6 +162,27 @@ func scanPricePerHourLine(scanner *bufio.Scanner, config *computerclub.Config) (
 	return nil, nil
 }
 
+func scanConfigOptionLines(scanner *lineScanner, config *computerclub.Config) (*InvalidLine, error) {
+	var invalidLine InvalidLine
+
+	for scanner.Scan() {
+		optionLine := scanner.Text()
+
+		if !isConfigOptionLine(optionLine) {
+			scanner.Unscan()
+			break
+		}
+
+		err := parseConfigOptionLine(optionLine, config)
+		if err != nil {
+			invalidLine = InvalidLine(optionLine)
+			return &invalidLine, newParseError(scanner.LineNumber(), optionLine, err, 0)
+		}
+	}
+
+	return nil, nil
+}
+
 func parseTablesCountLine(tablesCountLine string) (int, error) {
 	tablesCount, err := strconv.Atoi(tablesCountLine)
 	if err != nil {
@@ -166,8 +212,9 @@ func parseOpeningHoursLine(openingHoursLine string) (time.Time, time.Time, error
 		return time.Time{}, time.Time{}, err
 	}
 
+	// the club is open past midnight
 	if closingTime.Before(openingTime) {
-		return time.Time{}, time.Time{}, ErrInvalidFormatOpeningHours
+		closingTime = closingTime.Add(24 * time.Hour)
 	}
 
 	return openingTime, closingTime, nil
@@ -191,85 +238,254 @@ func parseTime(strTime string) (time.Time, error) {
 	return t, nil
 }
 
-func NewHandler(eventHandler eventhandler.Handler) *Handler {
-	return &Handler{
-		eventHandler: eventHandler,
+func (h *Handler) processEventLines(scanner *lineScanner, computerClubConfig *computerclub.Config) (*PeriodReport, *InvalidLine, error) {
+	var invalidLine InvalidLine
+
+	periodReport := &PeriodReport{}
+
+	eventClock := newEventClock(computerClubConfig)
+	isOpen := false
+
+	// workingDay stays zero for a file without dates
+	var workingDay time.Time
+
+	for scanner.Scan() {
+		eventLine := scanner.Text()
+
+		date, datedEventLine, isDated := cutDate(eventLine)
+		if isDated {
+			dayStarted, err := h.moveToDate(date, datedEventLine, eventClock, isOpen)
+			if err != nil {
+				invalidLine = InvalidLine(eventLine)
+				return nil, &invalidLine, newParseError(scanner.LineNumber(), eventLine, err, dateFieldOffset)
+			}
+
+			if dayStarted {
+				if h.isStatusTimeWithoutDate() {
+					return nil, nil, ErrStatusTimeWithoutDate
+				}
+
+				// the status is asked for before the day
+				if h.isAfterStatusTime(xtime.OnDate(eventClock.day, computerClubConfig.OpeningTime)) {
+					break
+				}
+
+				if isOpen {
+					err = h.closeWorkingDay(periodReport, workingDay)
+					if err != nil {
+						return nil, nil, err
+					}
+				}
+
+				workingDay = eventClock.day
+				h.eventHandler.OpenComputerClubOn(workingDay)
+				isOpen = true
+
+				err = h.writeDayStart(workingDay)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+
+			// date line
+			if datedEventLine == "" {
+				continue
+			}
+		}
+
+		if !isOpen {
+			h.eventHandler.OpenComputerClub()
+			isOpen = true
+
+			err := h.writeDayStart(workingDay)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		err := h.validateEventLine(datedEventLine, computerClubConfig.TablesCount, eventClock)
+		if err != nil {
+			invalidLine = InvalidLine(eventLine)
+			return nil, &invalidLine, newParseError(scanner.LineNumber(), eventLine, err, eventFieldOffset(isDated))
+		}
+
+		if h.isAfterStatusTime(eventClock.lastEventTime) {
+			break
+		}
+
+		event, err := eventhandler.FromEventLine(datedEventLine)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// events after midnight belong to the next day of an overnight club
+		event.Time = eventClock.lastEventTime
+
+		err = h.eventHandler.HandleEvent(event)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = h.writeOutcomes()
+		if err != nil {
+			return nil, nil, err
+		}
 	}
-}
 
-func (h *Handler) GetWorkingDayReport(filename string, computerClubTablesCount int) (WorkingDayReport, *InvalidLine, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return "", nil, err
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
 	}
-	defer file.Close()
 
-	fileInfo, err := file.Stat()
+	if !isOpen {
+		h.eventHandler.OpenComputerClub()
+
+		err := h.writeDayStart(workingDay)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// the status is taken from the working day, that is still open
+	if !h.statusTime.IsZero() {
+		if !workingDay.Equal(h.statusDate) {
+			return nil, nil, ErrStatusTimeOutOfFile
+		}
+		return periodReport, nil, nil
+	}
+
+	err := h.closeWorkingDay(periodReport, workingDay)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
-	if fileInfo.Size() < minFileLinesCount {
-		return "", nil, ErrInvalidFormatFile
+	if eventClock.dated {
+		err = h.closePeriod(periodReport)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return h.readFileByLine(file, computerClubTablesCount)
+	return periodReport, nil, nil
 }
 
-func (h *Handler) readFileByLine(file *os.File, computerClubTablesCount int) (WorkingDayReport, *InvalidLine, error) {
-	scanner := bufio.NewScanner(file)
+// moveToDate moves the event clock to the date of a date line or of an event
+// line starting with a date. It reports whether a new working day has started.
+func (h *Handler) moveToDate(date time.Time, eventLine string, eventClock *eventClock, isOpen bool) (bool, error) {
+	// events of a working day without date are already processed
+	if isOpen && !eventClock.dated {
+		return false, ErrInvalidFormatDate
+	}
 
-	h.moveScannerToFirstEventLine(scanner)
+	if eventLine == "" {
+		err := eventClock.startDay(date)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
 
-	return h.processEventLines(scanner, computerClubTablesCount)
+	strEventTime, _, _ := strings.Cut(eventLine, " ")
+
+	eventTime, err := parseTime(strEventTime)
+	if err != nil {
+		return false, ErrInvalidFormatEvent
+	}
+
+	return eventClock.moveTo(xtime.OnDate(date, eventTime))
 }
 
-func (h *Handler) moveScannerToFirstEventLine(scanner *bufio.Scanner) {
-	// skip config lines
-	for i := 1; i <= configLinesCount; i++ {
-		scanner.Scan()
+func (h *Handler) closeWorkingDay(periodReport *PeriodReport, workingDay time.Time) error {
+	h.eventHandler.CloseComputerClub()
+
+	if h.reportWriter != nil {
+		return h.writeOutcomes()
+	}
+
+	dayReport := DayReport{
+		Date:     workingDay,
+		Report:   WorkingDayReport(h.eventHandler.GetWorkingDayReport()),
+		Outcomes: h.eventHandler.GetOutcomes(),
 	}
+
+	periodReport.Days = append(periodReport.Days, dayReport)
+
+	return nil
 }
 
-func (h *Handler) processEventLines(scanner *bufio.Scanner, computerClubTablesCount int) (WorkingDayReport, *InvalidLine, error) {
-	h.eventHandler.OpenComputerClub()
+func (h *Handler) closePeriod(periodReport *PeriodReport) error {
+	if h.reportWriter != nil {
+		_, err := h.reportWriter.Write(h.eventHandler.GetPeriodReport())
+		return err
+	}
 
-	var invalidLine InvalidLine
-	var lastEventTime time.Time
+	periodReport.Summary = WorkingDayReport(h.eventHandler.GetPeriodReport())
+	periodReport.SummaryOutcomes = h.eventHandler.GetPeriodOutcomes()
 
-	for scanner.Scan() {
-		eventLine := scanner.Text()
-		err := h.validateEventLine(eventLine, computerClubTablesCount, &lastEventTime)
-		if err != nil {
-			invalidLine = InvalidLine(eventLine)
-			return "", &invalidLine, err
-		}
+	return nil
+}
 
-		event, err := eventhandler.FromEventLine(eventLine)
-		if err != nil {
-			return "", nil, err
-		}
+// writeDayStart writes the date line of the working day, that has just been
+// opened, and its opening time. The date line is omitted for a file without
+// dates.
+func (h *Handler) writeDayStart(workingDay time.Time) error {
+	if h.reportWriter == nil {
+		return nil
+	}
 
-		err = h.eventHandler.HandleEvent(event)
+	if !workingDay.IsZero() {
+		_, err := io.WriteString(h.reportWriter, xtime.FormatDate(workingDay)+"\n")
 		if err != nil {
-			return "", nil, err
+			return err
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return "", nil, err
+	return h.writeOutcomes()
+}
+
+// writeOutcomes writes the outcomes of the day, that are not written yet. They
+// are written, once the line is handled, so that an invalid line writes none.
+func (h *Handler) writeOutcomes() error {
+	if h.reportWriter == nil {
+		return nil
 	}
 
-	h.eventHandler.CloseComputerClub()
+	outcomes := h.eventHandler.TakeOutcomes()
+	if len(outcomes) == 0 {
+		return nil
+	}
 
-	workingDayReport := h.eventHandler.GetWorkingDayReport()
+	_, err := h.reportWriter.Write(computerclub.NewWorkingDayReport(outcomes))
 
-	return WorkingDayReport(workingDayReport), nil, nil
+	return err
 }
 
-func (h *Handler) validateEventLine(eventLine string, tablesCount int, lastEventTime *time.Time) error {
+// cutDate cuts the leading date off a date line or an event line. The returned
+// event line is empty for a date line.
+func cutDate(line string) (time.Time, string, bool) {
+	strDate, eventLine, _ := strings.Cut(line, " ")
+
+	if len(strDate) != len(dateLayout) {
+		return time.Time{}, line, false
+	}
+
+	date, err := xtime.ParseDateFromString(strDate)
+	if err != nil {
+		return time.Time{}, line, false
+	}
+
+	return date, eventLine, true
+}
+
+func eventFieldOffset(isDated bool) int {
+	if isDated {
+		return dateFieldOffset
+	}
+	return 0
+}
+
+func (h *Handler) validateEventLine(eventLine string, tablesCount int, eventClock *eventClock) error {
 	splitEventLine := strings.Split(eventLine, " ")
-	if len(splitEventLine) < minSplitEventLineLen || len(splitEventLine) > maxSplitEventLineLen {
+	if len(splitEventLine) < minSplitEventLineLen || len(splitEventLine) > reservationSplitEventLineLen {
 		return ErrInvalidFormatEvent
 	}
 
@@ -279,16 +495,19 @@ func (h *Handler) validateEventLine(eventLine string, tablesCount int, lastEvent
 	}
 
 	switch uint8(incomingEvent) {
-	case computerclub.IncomingEventClientArrived, computerclub.IncomingEventClientWaiting, computerclub.IncomingEventClientLeft:
-		return h.validateThreeArgsEvent(splitEventLine, lastEventTime)
-	case computerclub.IncomingEventClientTookPlace:
-		return h.validateFourArgsEvent(splitEventLine, tablesCount, lastEventTime)
+	case computerclub.IncomingEventClientArrived, computerclub.IncomingEventClientWaiting, computerclub.IncomingEventClientLeft,
+		computerclub.IncomingEventClientPaused, computerclub.IncomingEventClientResumed:
+		return h.validateThreeArgsEvent(splitEventLine, eventClock)
+	case computerclub.IncomingEventClientTookPlace, computerclub.IncomingEventTableOutOfService, computerclub.IncomingEventTableBackInService:
+		return h.validateFourArgsEvent(splitEventLine, tablesCount, eventClock)
+	case computerclub.IncomingEventClientReserved:
+		return h.validateReservationEvent(splitEventLine, tablesCount, eventClock)
 	default:
 		return ErrInvalidFormatEvent
 	}
 }
 
-func (h *Handler) validateThreeArgsEvent(splitEventLine []string, lastEventTime *time.Time) error {
+func (h *Handler) validateThreeArgsEvent(splitEventLine []string, eventClock *eventClock) error {
 	if len(splitEventLine) != minSplitEventLineLen {
 		return ErrInvalidFormatEvent
 	}
@@ -296,16 +515,14 @@ func (h *Handler) validateThreeArgsEvent(splitEventLine []string, lastEventTime
 
 	eventTime, err := parseTime(strEventTime)
 	if err != nil {
-		return err
+		return ErrInvalidFormatEventTime
 	}
 
-	err = h.validateEventSequence(eventTime, lastEventTime)
+	err = eventClock.tick(eventTime)
 	if err != nil {
 		return err
 	}
 
-	*lastEventTime = eventTime
-
 	clientName := splitEventLine[2]
 
 	err = h.validateClientName(clientName)
@@ -316,7 +533,7 @@ func (h *Handler) validateThreeArgsEvent(splitEventLine []string, lastEventTime
 	return nil
 }
 
-func (h *Handler) validateFourArgsEvent(splitEventLine []string, tablesCount int, lastEventTime *time.Time) error {
+func (h *Handler) validateFourArgsEvent(splitEventLine []string, tablesCount int, eventClock *eventClock) error {
 	if len(splitEventLine) != maxSplitEventLineLen {
 		return ErrInvalidFormatEvent
 	}
@@ -325,16 +542,14 @@ func (h *Handler) validateFourArgsEvent(splitEventLine []string, tablesCount int
 
 	eventTime, err := parseTime(strEventTime)
 	if err != nil {
-		return err
+		return ErrInvalidFormatEventTime
 	}
 
-	err = h.validateEventSequence(eventTime, lastEventTime)
+	err = eventClock.tick(eventTime)
 	if err != nil {
 		return err
 	}
 
-	*lastEventTime = eventTime
-
 	clientName := splitEventLine[2]
 
 	err = h.validateClientName(clientName)
@@ -351,33 +566,31 @@ func (h *Handler) validateFourArgsEvent(splitEventLine []string, tablesCount int
 	return nil
 }
 
-func (h *Handler) validateEventSequence(currentEventTime time.Time, lastEventTime *time.Time) error {
-	if !(*lastEventTime).IsZero() && currentEventTime.Before(*lastEventTime) {
-		return ErrInvalidFormatEventSequence
+func (h *Handler) validateReservationEvent(splitEventLine []string, tablesCount int, eventClock *eventClock) error {
+	if len(splitEventLine) != reservationSplitEventLineLen {
+		return ErrInvalidFormatEvent
 	}
 
-	return nil
-}
+	err := h.validateFourArgsEvent(splitEventLine[:maxSplitEventLineLen], tablesCount, eventClock)
+	if err != nil {
+		return err
+	}
 
-func (h *Handler) validateClientName(clientName string) error {
-	for i := 0; i < len(clientName); i++ {
-		if !h.isLowerCaseLetter(clientName[i]) && !h.isDigit(clientName[i]) && !h.isSpecialSymbol(clientName[i]) {
-			return ErrInvalidFormatClientName
+	for _, strReservationTime := range splitEventLine[maxSplitEventLineLen:] {
+		_, err = parseTime(strReservationTime)
+		if err != nil {
+			return ErrInvalidFormatReservation
 		}
 	}
-	return nil
-}
-
-func (h *Handler) isLowerCaseLetter(char byte) bool {
-	return char >= 'a' && char <= 'z'
-}
 
-func (h *Handler) isDigit(char byte) bool {
-	return char >= '0' && char <= '9'
+	return nil
 }
 
-func (h *Handler) isSpecialSymbol(char byte) bool {
-	return char == '_' || char == '-'
+func (h *Handler) validateClientName(clientName string) error {
+	if !eventhandler.IsValidClientName(clientName) {
+		return ErrInvalidFormatClientName
+	}
+	return nil
 }
 
 func (h *Handler) validateTableNumber(strTableNumber string, tablesCount int) error {
@@ -0,0 +1,114 @@
+package filehandler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidFormatJournalEntry = errors.New("invalid format of journal entry")
+
+// JournalEntry is a line accepted by the live club, numbered in the order of
+// acceptance.
+type JournalEntry struct {
+	Sequence int
+	Line     string
+}
+
+// Journal is an append-only file of the accepted lines. Every entry is on the
+// disk, once Append returns.
+type Journal struct {
+	file *os.File
+}
+
+// OpenJournal opens the journal file for appending, creating it if needed, and
+// returns the entries it already holds. An entry left half written by a crash
+// is dropped.
+func OpenJournal(filename string) (*Journal, []JournalEntry, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := readJournalEntries(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return &Journal{file: file}, entries, nil
+}
+
+// Append writes the entry to the end of the journal and waits for it to reach
+// the disk.
+func (j *Journal) Append(entry JournalEntry) error {
+	_, err := fmt.Fprintf(j.file, "%d %s\n", entry.Sequence, entry.Line)
+	if err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+// Truncate removes all entries of the journal, once they are kept elsewhere.
+func (j *Journal) Truncate() error {
+	err := j.file.Truncate(0)
+	if err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+func readJournalEntries(file *os.File) ([]JournalEntry, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// the part after the last line break was not written completely
+	completeLength := bytes.LastIndexByte(data, '\n') + 1
+	if completeLength < len(data) {
+		err = file.Truncate(int64(completeLength))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []JournalEntry
+
+	for i, line := range strings.Split(string(data[:completeLength]), "\n") {
+		if line == "" {
+			continue
+		}
+
+		entry, err := parseJournalEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %q: %w", i+1, line, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseJournalEntry(line string) (JournalEntry, error) {
+	strSequence, entryLine, ok := strings.Cut(line, " ")
+	if !ok || entryLine == "" {
+		return JournalEntry{}, ErrInvalidFormatJournalEntry
+	}
+
+	sequence, err := strconv.Atoi(strSequence)
+	if err != nil || sequence <= 0 {
+		return JournalEntry{}, ErrInvalidFormatJournalEntry
+	}
+
+	return JournalEntry{Sequence: sequence, Line: entryLine}, nil
+}
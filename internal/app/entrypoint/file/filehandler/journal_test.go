@@ -0,0 +1,84 @@
+package filehandler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "club.journal")
+
+	journal, entries, err := OpenJournal(filename)
+	if err != nil {
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	if len(entries) != 0 {
+		err = fmt.Errorf("expected no entries, got: '%v'", entries)
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	appendedEntries := []JournalEntry{
+		{Sequence: 1, Line: "open"},
+		{Sequence: 2, Line: "09:10 1 client1"},
+	}
+
+	for _, entry := range appendedEntries {
+		err = journal.Append(entry)
+		if err != nil {
+			t.Fatalf("TestJournal: %s", err.Error())
+		}
+	}
+
+	// the entry left half written by a crash is dropped
+	_, err = journal.file.WriteString("3 09:2")
+	if err != nil {
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	journal.Close()
+
+	journal, entries, err = OpenJournal(filename)
+	if err != nil {
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	if !slices.Equal(entries, appendedEntries) {
+		err = fmt.Errorf("expected entries: '%v', got: '%v'", appendedEntries, entries)
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	err = journal.Append(JournalEntry{Sequence: 3, Line: "09:20 2 client1 1"})
+	if err != nil {
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	journal.Close()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	expectedData := "1 open\n2 09:10 1 client1\n3 09:20 2 client1 1\n"
+
+	if string(data) != expectedData {
+		err = fmt.Errorf("expected journal: '%s', got: '%s'", expectedData, string(data))
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	err = os.WriteFile(filename, []byte("open\n"), 0644)
+	if err != nil {
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+
+	_, _, err = OpenJournal(filename)
+	if !errors.Is(err, ErrInvalidFormatJournalEntry) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrInvalidFormatJournalEntry, err)
+		t.Fatalf("TestJournal: %s", err.Error())
+	}
+}
@@ -0,0 +1,286 @@
+package filehandler
+
+import (
+	"encoding/json"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"time"
+)
+
+// JSONReportSchemaVersion is increased on every incompatible change of the
+// JSON report. The schema is described in README.md.
+const JSONReportSchemaVersion = 1
+
+const (
+	eventDirectionIncoming = "incoming"
+	eventDirectionOutgoing = "outgoing"
+)
+
+const layoutHoursMinutes = "15:04"
+
+type jsonReport struct {
+	SchemaVersion int         `json:"schema_version"`
+	Days          []jsonDay   `json:"days"`
+	Period        *jsonPeriod `json:"period,omitempty"`
+}
+
+type jsonDay struct {
+	Date        string             `json:"date,omitempty"`
+	OpeningTime *jsonTime          `json:"opening_time"`
+	ClosingTime *jsonTime          `json:"closing_time"`
+	Events      []jsonEvent        `json:"events"`
+	Tables      []jsonTableSummary `json:"tables"`
+	Categories  []jsonCategory     `json:"categories,omitempty"`
+	Wait        *jsonWaitSummary   `json:"wait,omitempty"`
+	Analytics   *jsonAnalytics     `json:"queue_analytics,omitempty"`
+}
+
+type jsonPeriod struct {
+	StartDate  string             `json:"start_date"`
+	EndDate    string             `json:"end_date"`
+	Tables     []jsonTableSummary `json:"tables"`
+	Categories []jsonCategory     `json:"categories,omitempty"`
+}
+
+// jsonTime has no date in a report of a file without dates, NextDay marks
+// times after midnight there.
+type jsonTime struct {
+	Date    string `json:"date,omitempty"`
+	Time    string `json:"time"`
+	NextDay bool   `json:"next_day,omitempty"`
+}
+
+type jsonEvent struct {
+	jsonTime
+	Direction  string `json:"direction"`
+	Id         uint8  `json:"id"`
+	ClientName string `json:"client,omitempty"`
+	TableId    int    `json:"table,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// ReservationStart and ReservationEnd are set for a reservation event
+	ReservationStart *jsonTime `json:"reservation_start,omitempty"`
+	ReservationEnd   *jsonTime `json:"reservation_end,omitempty"`
+}
+
+type jsonTableSummary struct {
+	TableId      int    `json:"table"`
+	Category     string `json:"category,omitempty"`
+	Revenue      int    `json:"revenue"`
+	UsageMinutes int    `json:"usage_minutes"`
+	// DowntimeMinutes is the time the table has been out of service
+	DowntimeMinutes int `json:"downtime_minutes,omitempty"`
+	// NoShows are the reservations of the table, that have expired untaken
+	NoShows []jsonNoShow `json:"no_shows,omitempty"`
+}
+
+type jsonNoShow struct {
+	ClientName       string   `json:"client"`
+	ReservationStart jsonTime `json:"reservation_start"`
+	ReservationEnd   jsonTime `json:"reservation_end"`
+}
+
+type jsonWaitSummary struct {
+	Clients            int `json:"clients"`
+	AverageWaitMinutes int `json:"average_wait_minutes"`
+	MaxWaitMinutes     int `json:"max_wait_minutes"`
+	GaveUp             int `json:"gave_up"`
+}
+
+type jsonAnalytics struct {
+	Waited             int `json:"waited"`
+	AverageWaitMinutes int `json:"average_wait_minutes"`
+	MaxWaitMinutes     int `json:"max_wait_minutes"`
+	GaveUp             int `json:"gave_up"`
+	TurnedAway         int `json:"turned_away"`
+	SentToWaitingArea  int `json:"sent_to_waiting_area"`
+	Rejected           int `json:"rejected"`
+	PeakQueueLength    int `json:"peak_queue_length"`
+	// PeakQueueTime is set, once anybody has waited
+	PeakQueueTime *jsonTime `json:"peak_queue_time,omitempty"`
+}
+
+type jsonCategory struct {
+	Category string `json:"category"`
+	Revenue  int    `json:"revenue"`
+}
+
+// JSONReport renders the period report in the JSON format.
+func (p *PeriodReport) JSONReport() ([]byte, error) {
+	report := jsonReport{
+		SchemaVersion: JSONReportSchemaVersion,
+		Days:          make([]jsonDay, 0, len(p.Days)),
+	}
+
+	for _, day := range p.Days {
+		report.Days = append(report.Days, newJSONDay(day))
+	}
+
+	if len(p.SummaryOutcomes) > 0 {
+		report.Period = newJSONPeriod(p.SummaryOutcomes)
+	}
+
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func newJSONDay(dayReport DayReport) jsonDay {
+	dated := !dayReport.Date.IsZero()
+
+	var firstDay time.Time
+	if len(dayReport.Outcomes) > 0 {
+		firstDay = xtime.StartOfDay(dayReport.Outcomes[0].Time)
+	}
+
+	day := jsonDay{
+		Events: make([]jsonEvent, 0, len(dayReport.Outcomes)),
+		Tables: make([]jsonTableSummary, 0),
+	}
+
+	if dated {
+		day.Date = xtime.FormatDate(dayReport.Date)
+	}
+
+	for _, outcome := range dayReport.Outcomes {
+		switch outcome.Kind {
+		case computerclub.OutcomeKindOpening:
+			openingTime := newJSONTime(outcome.Time, dated, firstDay)
+			day.OpeningTime = &openingTime
+		case computerclub.OutcomeKindClosing:
+			closingTime := newJSONTime(outcome.Time, dated, firstDay)
+			day.ClosingTime = &closingTime
+		case computerclub.OutcomeKindIncomingEvent, computerclub.OutcomeKindOutgoingEvent:
+			day.Events = append(day.Events, newJSONEvent(outcome, dated, firstDay))
+		case computerclub.OutcomeKindTableSummary:
+			day.Tables = append(day.Tables, newJSONTableSummary(outcome))
+		case computerclub.OutcomeKindNoShow:
+			// no-shows follow the summary of their table
+			table := &day.Tables[len(day.Tables)-1]
+			table.NoShows = append(table.NoShows, newJSONNoShow(outcome, dated, firstDay))
+		case computerclub.OutcomeKindCategorySummary:
+			day.Categories = append(day.Categories, newJSONCategory(outcome))
+		case computerclub.OutcomeKindWaitSummary:
+			day.Wait = newJSONWaitSummary(outcome.WaitStats)
+		case computerclub.OutcomeKindQueueAnalytics:
+			day.Analytics = newJSONAnalytics(outcome.WaitStats, outcome.QueueStats, dated, firstDay)
+		}
+	}
+
+	return day
+}
+
+func newJSONPeriod(outcomes []computerclub.Outcome) *jsonPeriod {
+	period := &jsonPeriod{
+		Tables: make([]jsonTableSummary, 0),
+	}
+
+	for _, outcome := range outcomes {
+		switch outcome.Kind {
+		case computerclub.OutcomeKindPeriod:
+			period.StartDate = xtime.FormatDate(outcome.StartDate)
+			period.EndDate = xtime.FormatDate(outcome.EndDate)
+		case computerclub.OutcomeKindTableSummary:
+			period.Tables = append(period.Tables, newJSONTableSummary(outcome))
+		case computerclub.OutcomeKindCategorySummary:
+			period.Categories = append(period.Categories, newJSONCategory(outcome))
+		}
+	}
+
+	return period
+}
+
+func newJSONTime(t time.Time, dated bool, firstDay time.Time) jsonTime {
+	formattedTime := jsonTime{
+		Time: t.Format(layoutHoursMinutes),
+	}
+
+	if dated {
+		formattedTime.Date = xtime.FormatDate(t)
+	} else {
+		formattedTime.NextDay = xtime.StartOfDay(t).After(firstDay)
+	}
+
+	return formattedTime
+}
+
+func newJSONEvent(outcome computerclub.Outcome, dated bool, firstDay time.Time) jsonEvent {
+	event := jsonEvent{
+		jsonTime:   newJSONTime(outcome.Time, dated, firstDay),
+		Direction:  eventDirection(outcome),
+		Id:         outcome.EventType,
+		ClientName: string(outcome.ClientName),
+		TableId:    int(outcome.TableId),
+	}
+
+	if outcome.Err != nil {
+		event.Error = outcome.Err.Error()
+	}
+
+	if outcome.Kind == computerclub.OutcomeKindIncomingEvent && outcome.EventType == computerclub.IncomingEventClientReserved {
+		reservationStart := newJSONTime(outcome.ReservationStart, dated, firstDay)
+		reservationEnd := newJSONTime(outcome.ReservationEnd, dated, firstDay)
+		event.ReservationStart = &reservationStart
+		event.ReservationEnd = &reservationEnd
+	}
+
+	return event
+}
+
+func eventDirection(outcome computerclub.Outcome) string {
+	if outcome.Kind == computerclub.OutcomeKindOutgoingEvent {
+		return eventDirectionOutgoing
+	}
+	return eventDirectionIncoming
+}
+
+func newJSONTableSummary(outcome computerclub.Outcome) jsonTableSummary {
+	return jsonTableSummary{
+		TableId:         int(outcome.TableId),
+		Category:        outcome.Category,
+		Revenue:         outcome.Profit,
+		UsageMinutes:    int(outcome.UsageTime.Minutes()),
+		DowntimeMinutes: int(outcome.Downtime.Minutes()),
+	}
+}
+
+func newJSONNoShow(outcome computerclub.Outcome, dated bool, firstDay time.Time) jsonNoShow {
+	return jsonNoShow{
+		ClientName:       string(outcome.ClientName),
+		ReservationStart: newJSONTime(outcome.ReservationStart, dated, firstDay),
+		ReservationEnd:   newJSONTime(outcome.ReservationEnd, dated, firstDay),
+	}
+}
+
+func newJSONWaitSummary(waitStats computerclub.WaitStats) *jsonWaitSummary {
+	return &jsonWaitSummary{
+		Clients:            waitStats.Clients,
+		AverageWaitMinutes: int(waitStats.AverageWait().Minutes()),
+		MaxWaitMinutes:     int(waitStats.MaxWait.Minutes()),
+		GaveUp:             waitStats.GaveUp,
+	}
+}
+
+func newJSONAnalytics(waitStats computerclub.WaitStats, queueStats computerclub.QueueStats, dated bool, firstDay time.Time) *jsonAnalytics {
+	analytics := &jsonAnalytics{
+		Waited:             waitStats.Clients,
+		AverageWaitMinutes: int(waitStats.AverageWait().Minutes()),
+		MaxWaitMinutes:     int(waitStats.MaxWait.Minutes()),
+		GaveUp:             waitStats.GaveUp,
+		TurnedAway:         queueStats.TurnedAway,
+		SentToWaitingArea:  queueStats.SentToWaitingArea,
+		Rejected:           queueStats.Rejected,
+		PeakQueueLength:    queueStats.PeakLength,
+	}
+
+	if queueStats.PeakLength > 0 {
+		peakQueueTime := newJSONTime(queueStats.PeakTime, dated, firstDay)
+		analytics.PeakQueueTime = &peakQueueTime
+	}
+
+	return analytics
+}
+
+func newJSONCategory(outcome computerclub.Outcome) jsonCategory {
+	return jsonCategory{
+		Category: outcome.Category,
+		Revenue:  outcome.Profit,
+	}
+}
@@ -0,0 +1,71 @@
+package filehandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSONReport(t *testing.T) {
+	const layout = "15:04"
+
+	openingTime, _ := time.Parse(layout, "09:00")
+	eventTime, _ := time.Parse(layout, "09:30")
+	closingTime, _ := time.Parse(layout, "19:00")
+
+	periodReport := PeriodReport{
+		Days: []DayReport{
+			{
+				Outcomes: []computerclub.Outcome{
+					{Kind: computerclub.OutcomeKindOpening, Time: openingTime},
+					{Kind: computerclub.OutcomeKindIncomingEvent, Time: eventTime, EventType: computerclub.IncomingEventClientTookPlace, ClientName: "client1", TableId: 1},
+					{Kind: computerclub.OutcomeKindOutgoingEvent, Time: eventTime, EventType: computerclub.OutgoingEventError, Err: computerclub.ErrClientUnknown},
+					{Kind: computerclub.OutcomeKindClosing, Time: closingTime},
+					{Kind: computerclub.OutcomeKindTableSummary, TableId: 1, Profit: 0, UsageTime: 0},
+				},
+			},
+		},
+	}
+
+	expectedJSONReport := `{
+		"schema_version": 1,
+		"days": [
+			{
+				"opening_time": {"time": "09:00"},
+				"closing_time": {"time": "19:00"},
+				"events": [
+					{"time": "09:30", "direction": "incoming", "id": 2, "client": "client1", "table": 1},
+					{"time": "09:30", "direction": "outgoing", "id": 13, "error": "ClientUnknown"}
+				],
+				"tables": [
+					{"table": 1, "revenue": 0, "usage_minutes": 0}
+				]
+			}
+		]
+	}`
+
+	jsonReport, err := periodReport.JSONReport()
+	if err != nil {
+		t.Fatalf("TestJSONReport: %s", err.Error())
+	}
+
+	var report, expectedReport any
+
+	err = json.Unmarshal(jsonReport, &report)
+	if err != nil {
+		t.Fatalf("TestJSONReport: %s", err.Error())
+	}
+
+	err = json.Unmarshal([]byte(expectedJSONReport), &expectedReport)
+	if err != nil {
+		t.Fatalf("TestJSONReport: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(report, expectedReport) {
+		err = fmt.Errorf("invalid json report: expected: '%v', got: '%v'", expectedJSONReport, string(jsonReport))
+		t.Fatalf("TestJSONReport: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package filehandler
+
+import "bufio"
+
+// lineScanner wraps bufio.Scanner with one line of look-ahead, so that optional
+// config lines can be told apart from the first event line.
+type lineScanner struct {
+	scanner    *bufio.Scanner
+	line       string
+	lineNumber int
+	unread     bool
+}
+
+func newLineScanner(scanner *bufio.Scanner) *lineScanner {
+	return &lineScanner{scanner: scanner}
+}
+
+func (s *lineScanner) Scan() bool {
+	if s.unread {
+		s.unread = false
+		return true
+	}
+
+	if !s.scanner.Scan() {
+		s.line = ""
+		return false
+	}
+
+	s.line = s.scanner.Text()
+	s.lineNumber++
+
+	return true
+}
+
+func (s *lineScanner) Text() string {
+	return s.line
+}
+
+// LineNumber returns the number of the current line, starting from 1.
+func (s *lineScanner) LineNumber() int {
+	return s.lineNumber
+}
+
+// Unscan makes the next Scan return the current line again.
+func (s *lineScanner) Unscan() {
+	s.unread = true
+}
+
+func (s *lineScanner) Err() error {
+	return s.scanner.Err()
+}
@@ -0,0 +1,195 @@
+package filehandler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError describes why a line of the file is invalid. Err is one of the
+// ErrInvalidFormat errors, Field and Column point at the offending part of the
+// line, Column is 1 when the line is wrong as a whole.
+type ParseError struct {
+	LineNumber int
+	Column     int
+	Field      string
+	Line       InvalidLine
+	Rule       string
+	Err        error
+}
+
+func (p *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %q: %s", p.LineNumber, string(p.Line), p.Err.Error())
+}
+
+func (p *ParseError) Unwrap() error {
+	return p.Err
+}
+
+// Diagnostic formats the error for the operator: the position, the reason and
+// the line itself with the offending field marked.
+func (p *ParseError) Diagnostic(filename string) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%s:%d:%d: %s: %s\n", filename, p.LineNumber, p.Column, p.Field, p.Err.Error())
+	fmt.Fprintf(&builder, "    %s\n", string(p.Line))
+	fmt.Fprintf(&builder, "    %s^\n", strings.Repeat(" ", p.Column-1))
+	fmt.Fprintf(&builder, "    rule: %s", p.Rule)
+
+	return builder.String()
+}
+
+// parseRule tells which field of a line an error is about. fieldIndex is the
+// position of the field in the line split by spaces, -1 for the whole line.
+type parseRule struct {
+	field      string
+	fieldIndex int
+	rule       string
+}
+
+var parseRules = map[error]parseRule{
+	ErrInvalidFormatFile: {
+		field:      "file",
+		fieldIndex: -1,
+		rule:       "file must contain tables count, opening hours and price per hour lines",
+	},
+	ErrInvalidFormatTablesCount: {
+		field:      "tables count",
+		fieldIndex: -1,
+		rule:       "tables count must be a positive integer",
+	},
+	ErrInvalidFormatOpeningHours: {
+		field:      "opening hours",
+		fieldIndex: -1,
+		rule:       "opening hours must be opening and closing time in HH:MM format separated by a space",
+	},
+	ErrInvalidFormatPricePerHour: {
+		field:      "price per hour",
+		fieldIndex: -1,
+		rule:       "price per hour must be a non-negative integer",
+	},
+	ErrInvalidFormatBillingPolicy: {
+		field:      "billing policy",
+		fieldIndex: 1,
+		rule:       "billing policy must be hourly, minute, block <minutes> or grace <minutes less than 60>",
+	},
+	ErrInvalidFormatTariff: {
+		field:      "tariff",
+		fieldIndex: 1,
+		rule:       "tariff must be <HH:MM> <HH:MM> <price per hour> [category] and must not overlap other tariffs of the category",
+	},
+	ErrInvalidFormatTableCategory: {
+		field:      "table category",
+		fieldIndex: 1,
+		rule:       "table category must be <name> <price per hour> <table number>... with a new name and tables without category",
+	},
+	ErrInvalidFormatPause: {
+		field:      "pause",
+		fieldIndex: 1,
+		rule:       "pause must be <max minutes> greater than zero",
+	},
+	ErrInvalidFormatQueuePolicy: {
+		field:      "queue policy",
+		fieldIndex: 1,
+		rule:       "queue policy must be fifo, priority or aging <minutes greater than zero>",
+	},
+	ErrInvalidFormatMembership: {
+		field:      "membership",
+		fieldIndex: 1,
+		rule:       "membership must be <level greater than zero> <client name>... with clients without membership",
+	},
+	ErrInvalidFormatQueueSize: {
+		field:      "queue size",
+		fieldIndex: 1,
+		rule:       "queue size must be a number of clients greater than zero",
+	},
+	ErrInvalidFormatOverflow: {
+		field:      "overflow policy",
+		fieldIndex: 1,
+		rule:       "overflow policy must be turn-away, waiting-area or partner-club",
+	},
+	ErrInvalidFormatMaxWait: {
+		field:      "max wait",
+		fieldIndex: 1,
+		rule:       "max wait must be <max minutes> greater than zero",
+	},
+	ErrInvalidFormatAnalytics: {
+		field:      "analytics",
+		fieldIndex: 1,
+		rule:       "analytics must have no arguments",
+	},
+	ErrInvalidFormatDate: {
+		field:      "date",
+		fieldIndex: -1,
+		rule:       "date must precede every event of a file with dates",
+	},
+	ErrInvalidFormatEvent: {
+		field:      "event",
+		fieldIndex: -1,
+		rule:       "event must be <HH:MM> <event id> <client name> [table number] [<HH:MM> <HH:MM>] with a known event id",
+	},
+	ErrInvalidFormatEventTime: {
+		field:      "event time",
+		fieldIndex: 0,
+		rule:       "event time must be in HH:MM format",
+	},
+	ErrInvalidFormatEventSequence: {
+		field:      "event sequence",
+		fieldIndex: 0,
+		rule:       "events and dates must not go back in time",
+	},
+	ErrInvalidFormatClientName: {
+		field:      "client name",
+		fieldIndex: 2,
+		rule:       "client name must consist of a-z, 0-9, _ and -",
+	},
+	ErrInvalidFormatTableNumber: {
+		field:      "table number",
+		fieldIndex: 3,
+		rule:       "table number must be one of the tables of the club",
+	},
+	ErrInvalidFormatReservation: {
+		field:      "reservation",
+		fieldIndex: 4,
+		rule:       "reservation must be held from <HH:MM> until <HH:MM>",
+	},
+}
+
+// newParseError describes the error of the line. fieldOffset is the number of
+// fields preceding the event, i.e. 1 for an event line starting with a date.
+func newParseError(lineNumber int, line string, err error, fieldOffset int) *ParseError {
+	parseRule, ok := parseRules[err]
+	if !ok {
+		parseRule = parseRules[ErrInvalidFormatEvent]
+	}
+
+	return &ParseError{
+		LineNumber: lineNumber,
+		Column:     fieldColumn(line, parseRule.fieldIndex, fieldOffset),
+		Field:      parseRule.field,
+		Line:       InvalidLine(line),
+		Rule:       parseRule.rule,
+		Err:        err,
+	}
+}
+
+// fieldColumn returns the 1-based column, where the field of the line starts.
+// The whole line is pointed at, if the line has no such field, e.g. a date line.
+func fieldColumn(line string, fieldIndex int, fieldOffset int) int {
+	if fieldIndex < 0 {
+		return 1
+	}
+
+	splitLine := strings.Split(line, " ")
+
+	fieldIndex += fieldOffset
+	if fieldIndex >= len(splitLine) {
+		return 1
+	}
+
+	column := 1
+	for _, field := range splitLine[:fieldIndex] {
+		column += len(field) + 1
+	}
+
+	return column
+}
@@ -0,0 +1,41 @@
+package filehandler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	content := "2\n" +
+		"09:00 19:00\n" +
+		"10\n" +
+		"2024-05-01\n" +
+		"09:10 1 client1\n" +
+		"2024-05-02 09:20 2 client1 3\n"
+
+	_, invalidLine, err := ReadPeriodReport(strings.NewReader(content), newTestEventHandler)
+
+	var parseError *ParseError
+	if !errors.As(err, &parseError) {
+		err = fmt.Errorf("expected parse error, got: '%v'", err)
+		t.Fatalf("TestParseError: %s", err.Error())
+	}
+
+	if *invalidLine != "2024-05-02 09:20 2 client1 3" || parseError.LineNumber != 6 || parseError.Column != 28 ||
+		!errors.Is(err, ErrInvalidFormatTableNumber) {
+		err = fmt.Errorf("expected table number error at 6:28, got: '%v' at %d:%d", err, parseError.LineNumber, parseError.Column)
+		t.Fatalf("TestParseError: %s", err.Error())
+	}
+
+	expectedDiagnostic := "events.txt:6:28: table number: invalid format of table number\n" +
+		"    2024-05-02 09:20 2 client1 3\n" +
+		"                               ^\n" +
+		"    rule: table number must be one of the tables of the club"
+
+	if diagnostic := parseError.Diagnostic("events.txt"); diagnostic != expectedDiagnostic {
+		err = fmt.Errorf("expected diagnostic: '%s', got: '%s'", expectedDiagnostic, diagnostic)
+		t.Fatalf("TestParseError: %s", err.Error())
+	}
+}
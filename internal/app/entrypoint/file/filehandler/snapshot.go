@@ -0,0 +1,89 @@
+package filehandler
+
+import (
+	"encoding/json"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"os"
+	"path/filepath"
+)
+
+// SnapshotFile is the content of the snapshot file.
+type SnapshotFile struct {
+	Snapshot computerclub.Snapshot
+	// JournalSequence is the sequence number of the last journal entry, that
+	// the snapshot includes, it is 0, when there is no journal
+	JournalSequence int `json:",omitempty"`
+}
+
+// WriteSnapshotFile writes the snapshot of the club to the file. The file is
+// replaced at once, so that it is never left half written, and is on the disk,
+// once the function returns.
+func WriteSnapshotFile(filename string, snapshot SnapshotFile) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(data)
+	if err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	err = tmpFile.Sync()
+	if err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpFile.Name(), filename)
+	if err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(filename))
+}
+
+// syncDir makes the renaming of a file in the directory survive a crash.
+func syncDir(dirname string) error {
+	dir, err := os.Open(dirname)
+	if err != nil {
+		return err
+	}
+
+	err = dir.Sync()
+	if err != nil {
+		dir.Close()
+		return err
+	}
+
+	return dir.Close()
+}
+
+// ReadSnapshotFile reads the snapshot of the club written by WriteSnapshotFile.
+func ReadSnapshotFile(filename string) (SnapshotFile, error) {
+	var snapshot SnapshotFile
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return snapshot, err
+	}
+
+	err = json.Unmarshal(data, &snapshot)
+	if err != nil {
+		return snapshot, computerclub.ErrInvalidSnapshot
+	}
+
+	return snapshot, nil
+}
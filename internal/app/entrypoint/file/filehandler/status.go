@@ -0,0 +1,159 @@
+package filehandler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFormatStatusTime = errors.New("invalid format of status time")
+	ErrStatusTimeWithoutDate   = errors.New("status time of a file with dates must have a date")
+	ErrStatusTimeOutOfFile     = errors.New("status time is not on a working day of the file")
+)
+
+var clientStateNames = map[uint8]string{
+	computerclub.StateClientArrived:         "arrived",
+	computerclub.StateClientTookPlace:       "seated",
+	computerclub.StateClientIsWaiting:       "waiting",
+	computerclub.StateClientIsPaused:        "paused",
+	computerclub.StateClientIsInWaitingArea: "waiting-area",
+}
+
+// Status is the state of the club at a moment of the working day.
+type Status struct {
+	Time    time.Time
+	Date    time.Time
+	Tables  []computerclub.TableOccupancy
+	Clients []computerclub.Client
+	Queue   []computerclub.QueuedClient
+	Revenue int
+}
+
+// ReadStatus reads the config and the events from the reader in a single pass
+// and returns the status of the club at the status time: HH:MM for a file
+// without dates, YYYY-MM-DD HH:MM for a file with dates. Events after the
+// status time are not handled, but everything, that happens by itself by the
+// status time, is.
+func ReadStatus(reader io.Reader, strStatusTime string, newEventHandler EventHandlerFactory) (*Status, *InvalidLine, error) {
+	statusDate, statusClock, err := parseStatusTime(strStatusTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scanner, config, invalidLine, err := readConfig(reader)
+	if err != nil {
+		return nil, invalidLine, err
+	}
+
+	h := &Handler{
+		eventHandler: newEventHandler(config),
+		statusTime:   getStatusTime(config, statusDate, statusClock),
+		statusDate:   statusDate,
+	}
+
+	_, invalidLine, err = h.processEventLines(scanner, config)
+	if err != nil {
+		return nil, invalidLine, err
+	}
+
+	// waits, pauses and reservations, that run out after the last event, are
+	// handled by the status time
+	h.eventHandler.AdvanceTo(h.statusTime)
+
+	status := &Status{
+		Time:    h.statusTime,
+		Date:    statusDate,
+		Tables:  h.eventHandler.GetOccupancy(),
+		Clients: h.eventHandler.GetClients(),
+		Queue:   h.eventHandler.GetQueue(h.statusTime),
+		Revenue: h.eventHandler.GetRunningRevenue(h.statusTime),
+	}
+
+	return status, nil, nil
+}
+
+func parseStatusTime(strStatusTime string) (time.Time, time.Time, error) {
+	// the date is zero, if there is none
+	date, strClock, _ := cutDate(strStatusTime)
+
+	clock, err := parseTime(strClock)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidFormatStatusTime
+	}
+
+	return date, clock, nil
+}
+
+// getStatusTime places the status time on the working day as the event clock
+// does with event times.
+func getStatusTime(config *computerclub.Config, statusDate time.Time, statusClock time.Time) time.Time {
+	day := statusDate
+	if day.IsZero() {
+		day = xtime.StartOfDay(config.OpeningTime)
+	}
+
+	statusTime := xtime.OnDate(day, statusClock)
+
+	// the status time after midnight belongs to the next day of an overnight club
+	if config.IsAfterMidnight(statusClock) {
+		statusTime = statusTime.Add(24 * time.Hour)
+	}
+
+	return statusTime
+}
+
+func (h *Handler) isAfterStatusTime(t time.Time) bool {
+	return !h.statusTime.IsZero() && t.After(h.statusTime)
+}
+
+func (h *Handler) isStatusTimeWithoutDate() bool {
+	return !h.statusTime.IsZero() && h.statusDate.IsZero()
+}
+
+// Report renders the status in the text format: the tables, the clients, the
+// queue and the revenue, each section is preceded by its name.
+func (s *Status) Report() string {
+	var builder strings.Builder
+
+	builder.WriteString("status ")
+	if !s.Date.IsZero() {
+		builder.WriteString(xtime.FormatDate(s.Date) + " ")
+	}
+	builder.WriteString(s.Time.Format(layoutHoursMinutes) + "\n")
+
+	builder.WriteString("tables\n")
+	for _, table := range s.Tables {
+		if table.IsPaused {
+			fmt.Fprintf(&builder, "%d paused %s\n", table.TableId, table.ClientName)
+		} else if table.IsBusy {
+			fmt.Fprintf(&builder, "%d busy %s %s\n", table.TableId, table.ClientName, table.BusySince.Format(layoutHoursMinutes))
+		} else if table.IsOutOfService {
+			fmt.Fprintf(&builder, "%d out-of-service\n", table.TableId)
+		} else {
+			fmt.Fprintf(&builder, "%d free\n", table.TableId)
+		}
+	}
+
+	builder.WriteString("clients\n")
+	for _, client := range s.Clients {
+		if client.State == computerclub.StateClientTookPlace || client.State == computerclub.StateClientIsPaused {
+			fmt.Fprintf(&builder, "%s %s %d\n", client.Name, clientStateNames[client.State], client.BusyTableId)
+		} else {
+			fmt.Fprintf(&builder, "%s %s\n", client.Name, clientStateNames[client.State])
+		}
+	}
+
+	builder.WriteString("queue\n")
+	for _, queuedClient := range s.Queue {
+		fmt.Fprintf(&builder, "%d %s\n", queuedClient.Position, queuedClient.ClientName)
+	}
+
+	fmt.Fprintf(&builder, "revenue %d\n", s.Revenue)
+
+	return builder.String()
+}
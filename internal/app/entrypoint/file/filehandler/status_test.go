@@ -0,0 +1,83 @@
+package filehandler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReadStatus(t *testing.T) {
+	content := "2\n" +
+		"09:00 19:00\n" +
+		"10\n" +
+		"09:10 1 client1\n" +
+		"09:20 2 client1 1\n" +
+		"09:30 1 client2\n" +
+		"09:40 1 client3\n" +
+		"09:50 2 client3 2\n" +
+		"10:00 3 client2\n" +
+		"10:30 4 client1\n"
+
+	status, _, err := ReadStatus(strings.NewReader(content), "10:20", newTestEventHandler)
+	if err != nil {
+		t.Fatalf("TestReadStatus: %s", err.Error())
+	}
+
+	expectedReport := "status 10:20\n" +
+		"tables\n" +
+		"1 busy client1 09:20\n" +
+		"2 busy client3 09:50\n" +
+		"clients\n" +
+		"client1 seated 1\n" +
+		"client2 waiting\n" +
+		"client3 seated 2\n" +
+		"queue\n" +
+		"1 client2\n" +
+		"revenue 20\n"
+
+	if report := status.Report(); report != expectedReport {
+		err = fmt.Errorf("expected status: '%s', got: '%s'", expectedReport, report)
+		t.Fatalf("TestReadStatus: %s", err.Error())
+	}
+
+	_, _, err = ReadStatus(strings.NewReader(content), "2024-05-01 10:20", newTestEventHandler)
+	if !errors.Is(err, ErrStatusTimeOutOfFile) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrStatusTimeOutOfFile, err)
+		t.Fatalf("TestReadStatus: %s", err.Error())
+	}
+}
+
+func TestReadStatusAfterExpiredWait(t *testing.T) {
+	content := "1\n" +
+		"09:00 19:00\n" +
+		"10\n" +
+		"max-wait 30\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 3 client2\n" +
+		"09:40 1 client3\n" +
+		"09:40 3 client3\n"
+
+	// the wait of client2 runs out at 09:50, after the last event
+	status, _, err := ReadStatus(strings.NewReader(content), "10:00", newTestEventHandler)
+	if err != nil {
+		t.Fatalf("TestReadStatusAfterExpiredWait: %s", err.Error())
+	}
+
+	expectedReport := "status 10:00\n" +
+		"tables\n" +
+		"1 busy client1 09:10\n" +
+		"clients\n" +
+		"client1 seated 1\n" +
+		"client3 waiting\n" +
+		"queue\n" +
+		"1 client3\n" +
+		"revenue 10\n"
+
+	if report := status.Report(); report != expectedReport {
+		err = fmt.Errorf("expected status: '%s', got: '%s'", expectedReport, report)
+		t.Fatalf("TestReadStatusAfterExpiredWait: %s", err.Error())
+	}
+}
@@ -0,0 +1,85 @@
+package filehandler
+
+import (
+	"bufio"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"io"
+)
+
+// EventHandlerFactory creates the event handler of the club, once its config is
+// read.
+type EventHandlerFactory func(config *computerclub.Config) eventhandler.Handler
+
+// StreamWorkingDayReport reads the config and the events from the reader in a
+// single pass and writes every line of the text report to the writer as soon
+// as it is known. The lines written before an invalid line stay written.
+func StreamWorkingDayReport(reader io.Reader, writer io.Writer, newEventHandler EventHandlerFactory) (*InvalidLine, error) {
+	_, invalidLine, err := readEvents(reader, writer, newEventHandler)
+	return invalidLine, err
+}
+
+// StreamValidWorkingDayReport streams the text report as StreamWorkingDayReport
+// does, but checks the whole input beforehand, so that nothing is written, if
+// there is an invalid line.
+func StreamValidWorkingDayReport(input io.ReadSeeker, writer io.Writer, newEventHandler EventHandlerFactory) (*InvalidLine, error) {
+	validationErrors, err := ValidateReader(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(validationErrors) > 0 {
+		invalidLine := InvalidLine(validationErrors[0].Line)
+		return &invalidLine, &validationErrors[0]
+	}
+
+	_, err = input.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return StreamWorkingDayReport(input, writer, newEventHandler)
+}
+
+// ReadPeriodReport reads the config and the events from the reader in a single
+// pass.
+func ReadPeriodReport(reader io.Reader, newEventHandler EventHandlerFactory) (*PeriodReport, *InvalidLine, error) {
+	return readEvents(reader, nil, newEventHandler)
+}
+
+func readEvents(reader io.Reader, writer io.Writer, newEventHandler EventHandlerFactory) (*PeriodReport, *InvalidLine, error) {
+	scanner, config, invalidLine, err := readConfig(reader)
+	if err != nil {
+		return nil, invalidLine, err
+	}
+
+	h := &Handler{
+		eventHandler: newEventHandler(config),
+		reportWriter: writer,
+	}
+
+	return h.processEventLines(scanner, config)
+}
+
+// readConfig reads the config lines and returns the scanner at the first event
+// line.
+func readConfig(reader io.Reader) (*lineScanner, *computerclub.Config, *InvalidLine, error) {
+	scanner := newLineScanner(bufio.NewScanner(reader))
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		return nil, nil, nil, ErrInvalidFormatFile
+	}
+	scanner.Unscan()
+
+	config := &computerclub.Config{}
+
+	invalidLine, err := processConfigLines(scanner, config)
+	if err != nil {
+		return nil, nil, invalidLine, err
+	}
+
+	return scanner, config, nil, nil
+}
@@ -0,0 +1,152 @@
+package filehandler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamWorkingDayReport(t *testing.T) {
+	eventsReader, eventsWriter := io.Pipe()
+	reportReader, reportWriter := io.Pipe()
+
+	streamErrs := make(chan error, 1)
+
+	go func() {
+		_, err := StreamWorkingDayReport(eventsReader, reportWriter, newTestEventHandler)
+		reportWriter.CloseWithError(err)
+		streamErrs <- err
+	}()
+
+	reportScanner := bufio.NewScanner(reportReader)
+
+	_, err := io.WriteString(eventsWriter, "1\n09:00 19:00\n10\n09:10 1 client1\n")
+	if err != nil {
+		t.Fatalf("TestStreamWorkingDayReport: %s", err.Error())
+	}
+
+	// the lines are written before the rest of events is read
+	for _, expectedLine := range []string{"09:00", "09:10 1 client1"} {
+		if !reportScanner.Scan() || reportScanner.Text() != expectedLine {
+			err = fmt.Errorf("expected line: '%s', got: '%s'", expectedLine, reportScanner.Text())
+			t.Fatalf("TestStreamWorkingDayReport: %s", err.Error())
+		}
+	}
+
+	_, err = io.WriteString(eventsWriter, "09:20 2 client1 1\n10:20 4 client1\n")
+	if err != nil {
+		t.Fatalf("TestStreamWorkingDayReport: %s", err.Error())
+	}
+	eventsWriter.Close()
+
+	var lines []string
+	for reportScanner.Scan() {
+		lines = append(lines, reportScanner.Text())
+	}
+
+	if err = <-streamErrs; err != nil {
+		t.Fatalf("TestStreamWorkingDayReport: %s", err.Error())
+	}
+
+	expectedLines := []string{"09:20 2 client1 1", "10:20 4 client1", "19:00", "1 10 01:00"}
+
+	if strings.Join(lines, "\n") != strings.Join(expectedLines, "\n") {
+		err = fmt.Errorf("expected lines: '%v', got: '%v'", expectedLines, lines)
+		t.Fatalf("TestStreamWorkingDayReport: %s", err.Error())
+	}
+}
+
+func TestStreamWorkingDayReportInvalidLine(t *testing.T) {
+	content := "1\n" +
+		"09:00 19:00\n" +
+		"10\n" +
+		"09:10 1 client1\n" +
+		"09:20 1 Client2\n"
+
+	var eventHandler eventhandler.Handler
+	newEventHandler := func(config *computerclub.Config) eventhandler.Handler {
+		eventHandler = newTestEventHandler(config)
+		return eventHandler
+	}
+
+	var report strings.Builder
+
+	invalidLine, err := StreamWorkingDayReport(strings.NewReader(content), &report, newEventHandler)
+	if !errors.Is(err, ErrInvalidFormatClientName) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrInvalidFormatClientName, err)
+		t.Fatalf("TestStreamWorkingDayReportInvalidLine: %s", err.Error())
+	}
+
+	// the lines before the invalid line stay written
+	expectedReport := "09:00\n" +
+		"09:10 1 client1\n"
+
+	if *invalidLine != "09:20 1 Client2" || report.String() != expectedReport {
+		err = fmt.Errorf("expected invalid line '09:20 1 Client2' after report '%s', got: '%s' after report '%s'", expectedReport, *invalidLine, report.String())
+		t.Fatalf("TestStreamWorkingDayReportInvalidLine: %s", err.Error())
+	}
+
+	// the written outcomes are not kept
+	if outcomes := eventHandler.GetOutcomes(); len(outcomes) != 0 {
+		err = fmt.Errorf("expected no outcomes kept, got: '%v'", outcomes)
+		t.Fatalf("TestStreamWorkingDayReportInvalidLine: %s", err.Error())
+	}
+}
+
+func TestStreamValidWorkingDayReport(t *testing.T) {
+	content := "1\n" +
+		"09:00 19:00\n" +
+		"10\n" +
+		"09:10 1 client1\n" +
+		"09:20 1 Client2\n"
+
+	var report strings.Builder
+
+	invalidLine, err := StreamValidWorkingDayReport(strings.NewReader(content), &report, newTestEventHandler)
+	if !errors.Is(err, ErrInvalidFormatClientName) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrInvalidFormatClientName, err)
+		t.Fatalf("TestStreamValidWorkingDayReport: %s", err.Error())
+	}
+
+	// nothing is written before the invalid line
+	if *invalidLine != "09:20 1 Client2" || report.Len() != 0 {
+		err = fmt.Errorf("expected only invalid line '09:20 1 Client2', got: '%s' and report '%s'", *invalidLine, report.String())
+		t.Fatalf("TestStreamValidWorkingDayReport: %s", err.Error())
+	}
+}
+
+func TestReadPeriodReport(t *testing.T) {
+	content := "1\n" +
+		"09:00 19:00\n" +
+		"10\n" +
+		"2024-05-01\n" +
+		"09:10 1 client1\n" +
+		"09:20 2 client1 1\n" +
+		"2024-05-02 10:00 1 client2\n"
+
+	periodReport, _, err := ReadPeriodReport(strings.NewReader(content), newTestEventHandler)
+	if err != nil {
+		t.Fatalf("TestReadPeriodReport: %s", err.Error())
+	}
+
+	var streamedReport strings.Builder
+
+	_, err = StreamWorkingDayReport(strings.NewReader(content), &streamedReport, newTestEventHandler)
+	if err != nil {
+		t.Fatalf("TestReadPeriodReport: %s", err.Error())
+	}
+
+	if string(periodReport.WorkingDayReport()) != streamedReport.String() {
+		err = fmt.Errorf("expected report: '%s', got: '%s'", periodReport.WorkingDayReport(), streamedReport.String())
+		t.Fatalf("TestReadPeriodReport: %s", err.Error())
+	}
+}
+
+func newTestEventHandler(config *computerclub.Config) eventhandler.Handler {
+	return eventhandler.NewHandler(computerclub.NewComputerClub(config))
+}
@@ -0,0 +1,153 @@
+package filehandler
+
+import (
+	"bufio"
+	"errors"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"io"
+	"math"
+	"os"
+)
+
+// ValidateFile checks the whole file and returns every invalid line, instead of
+// stopping at the first one as ReadPeriodReport does. Events are not handled.
+func ValidateFile(filename string) ([]ParseError, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if fileInfo.Size() < minFileLinesCount {
+		return nil, ErrInvalidFormatFile
+	}
+
+	return ValidateReader(file)
+}
+
+// ValidateReader checks the whole content of the reader as ValidateFile does.
+func ValidateReader(reader io.Reader) ([]ParseError, error) {
+	scanner := newLineScanner(bufio.NewScanner(reader))
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidFormatFile
+	}
+	scanner.Unscan()
+
+	config := computerclub.Config{}
+
+	validationErrors := validateConfigLines(scanner, &config)
+
+	// validation does not handle events
+	h := &Handler{}
+
+	validationErrors = append(validationErrors, h.validateEventLines(scanner, &config)...)
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return validationErrors, nil
+}
+
+// ValidateEventLine checks a single event line as the lines of the file are
+// checked, except that the order of events is not checked.
+func ValidateEventLine(eventLine string, config *computerclub.Config) error {
+	h := &Handler{}
+	return h.validateEventLine(eventLine, config.TablesCount, newEventClock(config))
+}
+
+func validateConfigLines(scanner *lineScanner, config *computerclub.Config) []ParseError {
+	var validationErrors []ParseError
+
+	configLineScanners := []func(*lineScanner, *computerclub.Config) (*InvalidLine, error){
+		scanTablesCountLine,
+		scanOpeningHoursLine,
+		scanPricePerHourLine,
+	}
+
+	for _, scanConfigLine := range configLineScanners {
+		invalidLine, err := scanConfigLine(scanner, config)
+		if err != nil {
+			validationErrors = append(validationErrors, *asParseError(err, scanner.LineNumber(), invalidLine))
+		}
+	}
+
+	for scanner.Scan() {
+		optionLine := scanner.Text()
+
+		if !isConfigOptionLine(optionLine) {
+			scanner.Unscan()
+			break
+		}
+
+		err := parseConfigOptionLine(optionLine, config)
+		if err != nil {
+			validationErrors = append(validationErrors, *newParseError(scanner.LineNumber(), optionLine, err, 0))
+		}
+	}
+
+	// table numbers can not be checked without tables count
+	if config.TablesCount < minTablesCount {
+		config.TablesCount = math.MaxInt
+	}
+
+	return validationErrors
+}
+
+func (h *Handler) validateEventLines(scanner *lineScanner, config *computerclub.Config) []ParseError {
+	var validationErrors []ParseError
+
+	eventClock := newEventClock(config)
+	hasEvents := false
+
+	for scanner.Scan() {
+		eventLine := scanner.Text()
+
+		date, datedEventLine, isDated := cutDate(eventLine)
+		if isDated {
+			_, err := h.moveToDate(date, datedEventLine, eventClock, hasEvents)
+			if err != nil {
+				validationErrors = append(validationErrors, *newParseError(scanner.LineNumber(), eventLine, err, dateFieldOffset))
+				continue
+			}
+
+			if datedEventLine == "" {
+				continue
+			}
+		}
+
+		hasEvents = true
+
+		err := h.validateEventLine(datedEventLine, config.TablesCount, eventClock)
+		if err != nil {
+			validationErrors = append(validationErrors, *newParseError(scanner.LineNumber(), eventLine, err, eventFieldOffset(isDated)))
+		}
+	}
+
+	return validationErrors
+}
+
+// asParseError returns the parse error wrapped in the error of the line or
+// builds one from the line.
+func asParseError(err error, lineNumber int, invalidLine *InvalidLine) *ParseError {
+	var parseError *ParseError
+	if errors.As(err, &parseError) {
+		return parseError
+	}
+
+	var line string
+	if invalidLine != nil {
+		line = string(*invalidLine)
+	}
+
+	return newParseError(lineNumber, line, err, 0)
+}
@@ -0,0 +1,75 @@
+package filehandler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateFile(t *testing.T) {
+	content := "2\n" +
+		"09:00 1900\n" +
+		"10\n" +
+		"08:48 1 Client1\n" +
+		"09:00 1 client2\n" +
+		"08:59 1 client3\n" +
+		"09:10 2 client2 3\n" +
+		"09:20 4 client2\n" +
+		"09:30 5 client2 1 12:00 1300\n"
+
+	filename := filepath.Join(t.TempDir(), "events.txt")
+
+	err := os.WriteFile(filename, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("TestValidateFile: %s", err.Error())
+	}
+
+	validationErrors, err := ValidateFile(filename)
+	if err != nil {
+		t.Fatalf("TestValidateFile: %s", err.Error())
+	}
+
+	expectedValidationErrors := []ParseError{
+		{LineNumber: 2, Column: 1, Line: "09:00 1900", Err: ErrInvalidFormatOpeningHours},
+		{LineNumber: 4, Column: 9, Line: "08:48 1 Client1", Err: ErrInvalidFormatClientName},
+		{LineNumber: 6, Column: 1, Line: "08:59 1 client3", Err: ErrInvalidFormatEventSequence},
+		{LineNumber: 7, Column: 17, Line: "09:10 2 client2 3", Err: ErrInvalidFormatTableNumber},
+		{LineNumber: 9, Column: 19, Line: "09:30 5 client2 1 12:00 1300", Err: ErrInvalidFormatReservation},
+	}
+
+	if len(validationErrors) != len(expectedValidationErrors) {
+		err = fmt.Errorf("expected %d validation errors, got: '%v'", len(expectedValidationErrors), validationErrors)
+		t.Fatalf("TestValidateFile: %s", err.Error())
+	}
+
+	for i, expectedValidationError := range expectedValidationErrors {
+		validationError := validationErrors[i]
+
+		if validationError.LineNumber != expectedValidationError.LineNumber ||
+			validationError.Column != expectedValidationError.Column ||
+			validationError.Line != expectedValidationError.Line ||
+			!errors.Is(&validationError, expectedValidationError.Err) {
+			err = fmt.Errorf("expected validation error: '%v', got: '%v'", expectedValidationError.Error(), validationError.Error())
+			t.Fatalf("TestValidateFile: %s", err.Error())
+		}
+	}
+}
+
+func TestAsParseError(t *testing.T) {
+	invalidLine := InvalidLine("09:00 1900")
+
+	parseError := asParseError(ErrInvalidFormatOpeningHours, 2, &invalidLine)
+	if parseError.LineNumber != 2 || parseError.Line != invalidLine || !errors.Is(parseError, ErrInvalidFormatOpeningHours) {
+		err := fmt.Errorf("expected parse error of line 2, got: '%v'", parseError.Error())
+		t.Fatalf("TestAsParseError: %s", err.Error())
+	}
+
+	wrappedParseError := fmt.Errorf("config: %w", parseError)
+
+	if asParseError(wrappedParseError, 3, nil) != parseError {
+		err := fmt.Errorf("expected parse error: '%v', got: '%v'", parseError.Error(), asParseError(wrappedParseError, 3, nil).Error())
+		t.Fatalf("TestAsParseError: %s", err.Error())
+	}
+}
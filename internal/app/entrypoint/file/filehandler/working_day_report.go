@@ -1,3 +1,44 @@
 package filehandler
 
+import (
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"strings"
+	"time"
+)
+
 type WorkingDayReport string
+
+// DayReport is the report of a single working day along with the outcomes it
+// is rendered from. Date is zero for a file without dates.
+type DayReport struct {
+	Date     time.Time
+	Report   WorkingDayReport
+	Outcomes []computerclub.Outcome
+}
+
+// PeriodReport holds the reports of all working days of a file and, for a file
+// with dates, the summary of the whole period.
+type PeriodReport struct {
+	Days            []DayReport
+	Summary         WorkingDayReport
+	SummaryOutcomes []computerclub.Outcome
+}
+
+// WorkingDayReport joins the day reports, each preceded by its date line, and
+// the period summary.
+func (p *PeriodReport) WorkingDayReport() WorkingDayReport {
+	var builder strings.Builder
+
+	for _, day := range p.Days {
+		if !day.Date.IsZero() {
+			builder.WriteString(xtime.FormatDate(day.Date))
+			builder.WriteString("\n")
+		}
+		builder.WriteString(string(day.Report))
+	}
+
+	builder.WriteString(string(p.Summary))
+
+	return WorkingDayReport(builder.String())
+}
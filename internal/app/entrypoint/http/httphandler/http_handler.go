@@ -0,0 +1,420 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	ErrClubIsNotOpen     = errors.New("ClubIsNotOpen")
+	ErrClubIsAlreadyOpen = errors.New("ClubIsAlreadyOpen")
+	ErrInvalidRequest    = errors.New("InvalidRequest")
+	ErrInvalidEventTime  = errors.New("InvalidEventTime")
+	ErrInvalidClientName = errors.New("InvalidClientName")
+	ErrInvalidTableId    = errors.New("InvalidTableId")
+	ErrEventSequence     = errors.New("EventIsEarlierThanPrevious")
+
+	// ErrInvalidReservationTime is the same for the window, that can not be
+	// parsed, and the window, that the club rejects
+	ErrInvalidReservationTime = computerclub.ErrInvalidReservationTime
+)
+
+// errorStatuses map the errors of events and requests to the response status
+var errorStatuses = map[error]int{
+	computerclub.ErrClientUnknown:     http.StatusNotFound,
+	computerclub.ErrNotOpenYet:        http.StatusForbidden,
+	computerclub.ErrYouShallNotPass:   http.StatusConflict,
+	computerclub.ErrPlaceIsBusy:       http.StatusConflict,
+	computerclub.ErrICanWaitNoLonger:  http.StatusConflict,
+	computerclub.ErrClientIsNotSeated: http.StatusConflict,
+	computerclub.ErrClientIsNotPaused: http.StatusConflict,
+
+	computerclub.ErrClientIsAlreadyWaiting: http.StatusConflict,
+	computerclub.ErrTableIsOutOfService:    http.StatusConflict,
+	computerclub.ErrTableIsInService:       http.StatusConflict,
+
+	ErrClubIsNotOpen:     http.StatusConflict,
+	ErrClubIsAlreadyOpen: http.StatusConflict,
+	ErrInvalidRequest:    http.StatusBadRequest,
+	ErrInvalidEventTime:  http.StatusBadRequest,
+	ErrInvalidClientName: http.StatusBadRequest,
+	ErrInvalidTableId:    http.StatusBadRequest,
+	ErrEventSequence:     http.StatusBadRequest,
+
+	ErrInvalidReservationTime: http.StatusBadRequest,
+}
+
+// sessionErrors name the errors of the session as the API does
+var sessionErrors = map[error]error{
+	eventhandler.ErrClubIsNotOpen:     ErrClubIsNotOpen,
+	eventhandler.ErrClubIsAlreadyOpen: ErrClubIsAlreadyOpen,
+	eventhandler.ErrEventSequence:     ErrEventSequence,
+}
+
+const layoutHoursMinutes = "15:04"
+
+type eventRequest struct {
+	Time       string `json:"time"`
+	ClientName string `json:"client"`
+	TableId    int    `json:"table,omitempty"`
+	// From and Until bound the window of a reservation
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+type eventsResponse struct {
+	Events []eventResponse `json:"events"`
+}
+
+type eventResponse struct {
+	Time       string `json:"time"`
+	Id         uint8  `json:"id"`
+	ClientName string `json:"client,omitempty"`
+	TableId    int    `json:"table,omitempty"`
+}
+
+type tableResponse struct {
+	TableId        int    `json:"table"`
+	Category       string `json:"category"`
+	IsBusy         bool   `json:"busy"`
+	IsOutOfService bool   `json:"out_of_service,omitempty"`
+	IsPaused       bool   `json:"paused,omitempty"`
+	ClientName     string `json:"client,omitempty"`
+	BusySince      string `json:"busy_since,omitempty"`
+}
+
+type queueResponse struct {
+	ClientNames []string `json:"clients"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	// Events are the outgoing events, that happened by themselves by the time
+	// of the rejected event, e.g. expired waits
+	Events []eventResponse `json:"events,omitempty"`
+}
+
+// Handler serves the computer club over HTTP. Every working day is handled by
+// a new service, so that it starts with free tables and no clients.
+type Handler struct {
+	config                 *computerclub.Config
+	newComputerClubService func(config *computerclub.Config) computerclub.ComputerClubService
+
+	mu                  sync.Mutex
+	computerClubService computerclub.ComputerClubService
+	session             *eventhandler.Session
+}
+
+func NewHandler(config *computerclub.Config, newComputerClubService func(config *computerclub.Config) computerclub.ComputerClubService) *Handler {
+	return &Handler{
+		config:                 config,
+		newComputerClubService: newComputerClubService,
+		session:                eventhandler.NewSession(config),
+	}
+}
+
+// Routes returns the handler of all endpoints of the API.
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /open", h.handleOpen)
+	mux.HandleFunc("POST /close", h.handleClose)
+
+	mux.HandleFunc("POST /events/arrive", h.handleEvent(computerclub.IncomingEventClientArrived))
+	mux.HandleFunc("POST /events/take-place", h.handleEvent(computerclub.IncomingEventClientTookPlace))
+	mux.HandleFunc("POST /events/wait", h.handleEvent(computerclub.IncomingEventClientWaiting))
+	mux.HandleFunc("POST /events/leave", h.handleEvent(computerclub.IncomingEventClientLeft))
+	mux.HandleFunc("POST /events/reserve", h.handleEvent(computerclub.IncomingEventClientReserved))
+	mux.HandleFunc("POST /events/out-of-service", h.handleEvent(computerclub.IncomingEventTableOutOfService))
+	mux.HandleFunc("POST /events/back-in-service", h.handleEvent(computerclub.IncomingEventTableBackInService))
+	mux.HandleFunc("POST /events/pause", h.handleEvent(computerclub.IncomingEventClientPaused))
+	mux.HandleFunc("POST /events/resume", h.handleEvent(computerclub.IncomingEventClientResumed))
+
+	mux.HandleFunc("GET /tables", h.handleGetTables)
+	mux.HandleFunc("GET /queue", h.handleGetQueue)
+	mux.HandleFunc("GET /report", h.handleGetReport)
+
+	return mux
+}
+
+func (h *Handler) handleOpen(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	err := h.session.Open()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.computerClubService = h.newComputerClubService(h.config)
+	h.computerClubService.Open()
+
+	writeJSON(w, http.StatusOK, h.takeEvents())
+}
+
+func (h *Handler) handleClose(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	err := h.session.Close()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.computerClubService.Close()
+
+	writeJSON(w, http.StatusOK, h.takeEvents())
+}
+
+func (h *Handler) handleEvent(eventType uint8) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request eventRequest
+
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			writeError(w, ErrInvalidRequest)
+			return
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		err = h.processEvent(eventType, &request)
+
+		// the error event is responded as the error, the other events are
+		// responded with it, as they are taken anyway
+		events := h.takeEvents()
+		if err != nil {
+			writeErrorWithEvents(w, err, events.Events)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, events)
+	}
+}
+
+func (h *Handler) processEvent(eventType uint8, request *eventRequest) error {
+	eventTime, err := h.parseEventTime(request.Time)
+	if err != nil {
+		return err
+	}
+
+	if !eventhandler.IsValidClientName(request.ClientName) {
+		return ErrInvalidClientName
+	}
+
+	clientName := computerclub.ClientName(request.ClientName)
+
+	switch eventType {
+	case computerclub.IncomingEventClientArrived:
+		err = h.computerClubService.ProcessEventClientArrived(eventTime, clientName)
+	case computerclub.IncomingEventClientTookPlace:
+		if !h.isValidTableId(request.TableId) {
+			return ErrInvalidTableId
+		}
+		err = h.computerClubService.ProcessEventClientTookPlace(eventTime, clientName, computerclub.TableId(request.TableId))
+	case computerclub.IncomingEventClientWaiting:
+		err = h.computerClubService.ProcessEventClientWaiting(eventTime, clientName)
+		// the overflow policy handles the client instead, which is responded
+		// as the outgoing event
+		if errors.Is(err, computerclub.ErrQueueIsFull) {
+			err = nil
+		}
+	case computerclub.IncomingEventClientLeft:
+		err = h.computerClubService.ProcessEventClientLeft(eventTime, clientName)
+	case computerclub.IncomingEventClientReserved:
+		if !h.isValidTableId(request.TableId) {
+			return ErrInvalidTableId
+		}
+
+		startTime, endTime, windowErr := parseReservationWindow(eventTime, request.From, request.Until)
+		if windowErr != nil {
+			return windowErr
+		}
+
+		err = h.computerClubService.ProcessEventClientReserved(eventTime, clientName, computerclub.TableId(request.TableId), startTime, endTime)
+	case computerclub.IncomingEventTableOutOfService:
+		if !h.isValidTableId(request.TableId) {
+			return ErrInvalidTableId
+		}
+		err = h.computerClubService.ProcessEventTableOutOfService(eventTime, computerclub.StaffName(clientName), computerclub.TableId(request.TableId))
+	case computerclub.IncomingEventTableBackInService:
+		if !h.isValidTableId(request.TableId) {
+			return ErrInvalidTableId
+		}
+		err = h.computerClubService.ProcessEventTableBackInService(eventTime, computerclub.StaffName(clientName), computerclub.TableId(request.TableId))
+	case computerclub.IncomingEventClientPaused:
+		err = h.computerClubService.ProcessEventClientPaused(eventTime, clientName)
+	case computerclub.IncomingEventClientResumed:
+		err = h.computerClubService.ProcessEventClientResumed(eventTime, clientName)
+	}
+
+	h.session.AddEvent(eventTime)
+
+	return err
+}
+
+// parseEventTime parses the event time and places it on the working day of the
+// session.
+func (h *Handler) parseEventTime(strEventTime string) (time.Time, error) {
+	if !h.session.IsOpen() {
+		return time.Time{}, ErrClubIsNotOpen
+	}
+
+	eventTime, err := xtime.ParseHoursMinutesFromString(strEventTime)
+	if err != nil {
+		return time.Time{}, ErrInvalidEventTime
+	}
+
+	return h.session.EventTime(eventTime)
+}
+
+func (h *Handler) isValidTableId(tableId int) bool {
+	return tableId >= 1 && tableId <= h.config.TablesCount
+}
+
+// parseReservationWindow places the window of the reservation after the event,
+// so the window may continue after midnight.
+func parseReservationWindow(eventTime time.Time, strFrom string, strUntil string) (time.Time, time.Time, error) {
+	from, err := xtime.ParseHoursMinutesFromString(strFrom)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidReservationTime
+	}
+
+	until, err := xtime.ParseHoursMinutesFromString(strUntil)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidReservationTime
+	}
+
+	startTime := xtime.NotBefore(eventTime, from)
+
+	return startTime, xtime.NotBefore(startTime, until), nil
+}
+
+func (h *Handler) handleGetTables(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.computerClubService == nil {
+		writeError(w, ErrClubIsNotOpen)
+		return
+	}
+
+	occupancy := h.computerClubService.GetOccupancy()
+
+	response := make([]tableResponse, 0, len(occupancy))
+	for _, tableOccupancy := range occupancy {
+		tableResp := tableResponse{
+			TableId:        int(tableOccupancy.TableId),
+			Category:       tableOccupancy.Category,
+			IsBusy:         tableOccupancy.IsBusy,
+			IsOutOfService: tableOccupancy.IsOutOfService,
+			IsPaused:       tableOccupancy.IsPaused,
+			ClientName:     string(tableOccupancy.ClientName),
+		}
+		if tableOccupancy.IsBusy && !tableOccupancy.IsPaused {
+			tableResp.BusySince = tableOccupancy.BusySince.Format(layoutHoursMinutes)
+		}
+		response = append(response, tableResp)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) handleGetQueue(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.computerClubService == nil {
+		writeError(w, ErrClubIsNotOpen)
+		return
+	}
+
+	response := queueResponse{
+		ClientNames: make([]string, 0),
+	}
+	// the queue is ordered as of the last event
+	for _, queuedClient := range h.computerClubService.GetQueue(h.session.LastEventTime()) {
+		response.ClientNames = append(response.ClientNames, string(queuedClient.ClientName))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetReport responds the working day report so far in the text format.
+func (h *Handler) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.computerClubService == nil {
+		writeError(w, ErrClubIsNotOpen)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.computerClubService.GetWorkingDayReport())
+}
+
+// takeEvents returns the outgoing events, that are not responded yet.
+func (h *Handler) takeEvents() eventsResponse {
+	response := eventsResponse{
+		Events: make([]eventResponse, 0),
+	}
+
+	if h.computerClubService == nil {
+		return response
+	}
+
+	outcomes := h.session.TakeOutcomes(h.computerClubService.GetOutcomesFrom)
+
+	for _, outcome := range outcomes {
+		if outcome.Kind != computerclub.OutcomeKindOutgoingEvent || outcome.Err != nil {
+			continue
+		}
+
+		response.Events = append(response.Events, eventResponse{
+			Time:       outcome.Time.Format(layoutHoursMinutes),
+			Id:         outcome.EventType,
+			ClientName: string(outcome.ClientName),
+			TableId:    int(outcome.TableId),
+		})
+	}
+
+	return response
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeErrorWithEvents(w, err, nil)
+}
+
+func writeErrorWithEvents(w http.ResponseWriter, err error, events []eventResponse) {
+	if apiErr, ok := sessionErrors[err]; ok {
+		err = apiErr
+	}
+
+	status := http.StatusInternalServerError
+
+	for knownErr, knownStatus := range errorStatuses {
+		if errors.Is(err, knownErr) {
+			status = knownStatus
+			break
+		}
+	}
+
+	writeJSON(w, status, errorResponse{Error: err.Error(), Events: events})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
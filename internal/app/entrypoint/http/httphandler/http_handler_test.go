@@ -0,0 +1,136 @@
+package httphandler
+
+import (
+	"fmt"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	openingTime, _ := xtime.ParseHoursMinutesFromString("09:00")
+	closingTime, _ := xtime.ParseHoursMinutesFromString("19:00")
+
+	config := &computerclub.Config{
+		TablesCount:  1,
+		OpeningTime:  openingTime,
+		ClosingTime:  closingTime,
+		PricePerHour: 10,
+	}
+
+	handler := NewHandler(config, computerclub.NewComputerClub)
+
+	server := httptest.NewServer(handler.Routes())
+	defer server.Close()
+
+	requests := []struct {
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{http.MethodPost, "/events/arrive", `{"time":"09:10","client":"client1"}`, http.StatusConflict, `{"error":"ClubIsNotOpen"}`},
+		{http.MethodPost, "/open", "", http.StatusOK, `{"events":[]}`},
+		{http.MethodPost, "/events/arrive", `{"time":"08:50","client":"client1"}`, http.StatusForbidden, `{"error":"NotOpenYet"}`},
+		{http.MethodPost, "/events/arrive", `{"time":"09:10","client":"client1"}`, http.StatusOK, `{"events":[]}`},
+		{http.MethodPost, "/events/take-place", `{"time":"09:20","client":"client2","table":1}`, http.StatusNotFound, `{"error":"ClientUnknown"}`},
+		{http.MethodPost, "/events/take-place", `{"time":"09:20","client":"client1","table":1}`, http.StatusOK, `{"events":[]}`},
+		{http.MethodPost, "/events/take-place", `{"time":"09:20","client":"client1","table":2}`, http.StatusBadRequest, `{"error":"InvalidTableId"}`},
+		{http.MethodPost, "/events/arrive", `{"time":"09:30","client":"client2"}`, http.StatusOK, `{"events":[]}`},
+		{http.MethodPost, "/events/take-place", `{"time":"09:30","client":"client2","table":1}`, http.StatusConflict, `{"error":"PlaceIsBusy"}`},
+		{http.MethodPost, "/events/wait", `{"time":"09:40","client":"client2"}`, http.StatusOK, `{"events":[]}`},
+		{http.MethodGet, "/tables", "", http.StatusOK, `[{"table":1,"category":"standard","busy":true,"client":"client1","busy_since":"09:20"}]`},
+		{http.MethodGet, "/queue", "", http.StatusOK, `{"clients":["client2"]}`},
+		{http.MethodPost, "/events/arrive", `{"time":"09:35","client":"client3"}`, http.StatusBadRequest, `{"error":"EventIsEarlierThanPrevious"}`},
+		{http.MethodPost, "/events/leave", `{"time":"10:10","client":"client1"}`, http.StatusOK, `{"events":[{"time":"10:10","id":12,"client":"client2","table":1}]}`},
+		{http.MethodPost, "/events/leave", `{"time":"10:10","client":"Client1"}`, http.StatusBadRequest, `{"error":"InvalidClientName"}`},
+		{http.MethodPost, "/events/leave", `{"time":"10:10"`, http.StatusBadRequest, `{"error":"InvalidRequest"}`},
+		{http.MethodPost, "/events/pause", `{"time":"10:20","client":"client2"}`, http.StatusOK, `{"events":[]}`},
+		{http.MethodPost, "/events/resume", `{"time":"10:30","client":"client2"}`, http.StatusOK, `{"events":[]}`},
+		{http.MethodPost, "/events/reserve", `{"time":"10:40","client":"client3","table":1,"from":"12:00","until":"13:00"}`, http.StatusOK, `{"events":[]}`},
+		{http.MethodPost, "/events/out-of-service", `{"time":"11:00","client":"admin","table":1}`, http.StatusOK, `{"events":[{"time":"11:00","id":15,"client":"client2"}]}`},
+		{http.MethodPost, "/events/out-of-service", `{"time":"11:10","client":"admin","table":1}`, http.StatusConflict, `{"error":"TableIsOutOfService"}`},
+		{http.MethodPost, "/events/back-in-service", `{"time":"11:30","client":"admin","table":1}`, http.StatusOK, `{"events":[{"time":"11:30","id":12,"client":"client2","table":1}]}`},
+		// the reservation expires before the rejected event
+		{http.MethodPost, "/events/resume", `{"time":"13:10","client":"client2"}`, http.StatusConflict, `{"error":"ClientIsNotPaused","events":[{"time":"13:00","id":14,"client":"client3","table":1}]}`},
+		{http.MethodPost, "/close", "", http.StatusOK, `{"events":[{"time":"19:00","id":11,"client":"client2"}]}`},
+		{http.MethodGet, "/report", "", http.StatusOK, "09:00\n08:50 1 client1\n08:50 13 NotOpenYet\n09:10 1 client1\n" +
+			"09:20 2 client2 1\n09:20 13 ClientUnknown\n09:20 2 client1 1\n09:30 1 client2\n09:30 2 client2 1\n" +
+			"09:30 13 PlaceIsBusy\n09:40 3 client2\n10:10 4 client1\n10:10 12 client2 1\n10:20 8 client2\n10:30 9 client2\n" +
+			"10:40 5 client3 1 12:00 13:00\n11:00 6 admin 1\n11:00 15 client2\n11:10 6 admin 1\n11:10 13 TableIsOutOfService\n" +
+			"11:30 7 admin 1\n11:30 12 client2 1\n13:00 14 client3 1\n13:10 9 client2\n13:10 13 ClientIsNotPaused\n" +
+			"19:00 11 client2\n19:00\n1 100 09:00\n1 downtime 00:30\n1 no-show client3 12:00 13:00\n"},
+	}
+
+	for _, request := range requests {
+		httpRequest, err := http.NewRequest(request.method, server.URL+request.path, strings.NewReader(request.body))
+		if err != nil {
+			t.Fatalf("TestHandler: %s", err.Error())
+		}
+
+		response, err := http.DefaultClient.Do(httpRequest)
+		if err != nil {
+			t.Fatalf("TestHandler: %s", err.Error())
+		}
+
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			t.Fatalf("TestHandler: %s", err.Error())
+		}
+
+		if response.StatusCode != request.expectedStatus || strings.TrimSuffix(string(body), "\n") != strings.TrimSuffix(request.expectedBody, "\n") {
+			err = fmt.Errorf("expected %d '%s' for %s %s, got: %d '%s'",
+				request.expectedStatus, request.expectedBody, request.method, request.path, response.StatusCode, body)
+			t.Fatalf("TestHandler: %s", err.Error())
+		}
+	}
+}
+
+func TestHandlerInvalidReservationTime(t *testing.T) {
+	openingTime, _ := xtime.ParseHoursMinutesFromString("09:00")
+	closingTime, _ := xtime.ParseHoursMinutesFromString("19:00")
+
+	config := &computerclub.Config{
+		TablesCount:  1,
+		OpeningTime:  openingTime,
+		ClosingTime:  closingTime,
+		PricePerHour: 10,
+	}
+
+	handler := NewHandler(config, computerclub.NewComputerClub)
+
+	server := httptest.NewServer(handler.Routes())
+	defer server.Close()
+
+	response, err := http.Post(server.URL+"/open", "application/json", nil)
+	if err != nil {
+		t.Fatalf("TestHandlerInvalidReservationTime: %s", err.Error())
+	}
+	response.Body.Close()
+
+	// the window ends after closing
+	response, err = http.Post(server.URL+"/events/reserve", "application/json",
+		strings.NewReader(`{"time":"09:10","client":"client1","table":1,"from":"18:30","until":"19:30"}`))
+	if err != nil {
+		t.Fatalf("TestHandlerInvalidReservationTime: %s", err.Error())
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		t.Fatalf("TestHandlerInvalidReservationTime: %s", err.Error())
+	}
+
+	expectedBody := `{"error":"InvalidReservationTime"}`
+
+	if response.StatusCode != http.StatusBadRequest || strings.TrimSuffix(string(body), "\n") != expectedBody {
+		err = fmt.Errorf("expected %d '%s', got: %d '%s'", http.StatusBadRequest, expectedBody, response.StatusCode, body)
+		t.Fatalf("TestHandlerInvalidReservationTime: %s", err.Error())
+	}
+}
@@ -0,0 +1,352 @@
+package sockethandler
+
+import (
+	"bufio"
+	"errors"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/file/filehandler"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrClubIsNotOpen     = eventhandler.ErrClubIsNotOpen
+	ErrClubIsAlreadyOpen = eventhandler.ErrClubIsAlreadyOpen
+	ErrEventSequence     = eventhandler.ErrEventSequence
+)
+
+const (
+	commandOpen  = "open"
+	commandClose = "close"
+)
+
+// errorLinePrefix starts the line sent back for a line, that can not be handled
+const errorLinePrefix = "ERR "
+
+// Server runs the computer club live: it accepts the event lines of the file
+// format and the open and close commands over connections, and sends the lines
+// of the report produced by each of them back to the connection at once.
+// Incoming events are not echoed. All connections share the same club.
+type Server struct {
+	config          *computerclub.Config
+	newEventHandler func(config *computerclub.Config) eventhandler.Handler
+
+	mu           sync.Mutex
+	eventHandler eventhandler.Handler
+	session      *eventhandler.Session
+
+	// snapshotFilename is the file, that keeps the state of the club, it is
+	// empty, when the state is not kept
+	snapshotFilename string
+	// journal keeps the accepted lines, it is nil, when there is no journal
+	journal *filehandler.Journal
+	// journalSequence is the sequence number of the last accepted line
+	journalSequence int
+	// snapshotSequence is the sequence number of the last line, that the
+	// snapshot file includes
+	snapshotSequence int
+	// snapshotInterval is the number of journal entries between snapshots
+	snapshotInterval int
+}
+
+// NewServer creates the server of the club. Every working day is handled by a
+// new event handler, so that it starts with free tables and no clients.
+func NewServer(config *computerclub.Config, newEventHandler func(config *computerclub.Config) eventhandler.Handler) *Server {
+	return &Server{
+		config:          config,
+		newEventHandler: newEventHandler,
+		session:         eventhandler.NewSession(config),
+	}
+}
+
+// UseSnapshotFile makes the server keep the state of the club in the file after
+// every line, or once in a while, when there is a journal. The state is restored
+// from the file, if it exists, so that the working day continues after a restart.
+func (s *Server) UseSnapshotFile(filename string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshotFilename = filename
+
+	snapshotFile, err := filehandler.ReadSnapshotFile(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	err = s.restore(snapshotFile.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	s.journalSequence = snapshotFile.JournalSequence
+	s.snapshotSequence = snapshotFile.JournalSequence
+
+	return nil
+}
+
+// UseJournalFile makes the server append every accepted line to the journal
+// file before the line is applied, and write the snapshot only once per the
+// snapshot interval of lines. A line is accepted, once it is fully checked, so
+// the journal keeps no rejected lines. The lines of the journal, that the
+// restored snapshot does not include, are applied again, so UseSnapshotFile
+// must be called first.
+func (s *Server) UseJournalFile(filename string, snapshotInterval int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	journal, entries, err := filehandler.OpenJournal(filename)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Sequence <= s.journalSequence {
+			continue
+		}
+
+		err = s.applyLine(entry.Line)
+		if err != nil {
+			journal.Close()
+			return err
+		}
+		s.takeOutcomes()
+
+		s.journalSequence = entry.Sequence
+	}
+
+	s.journal = journal
+	s.snapshotInterval = snapshotInterval
+
+	return nil
+}
+
+func (s *Server) restore(snapshot computerclub.Snapshot) error {
+	eventHandler := s.newEventHandler(s.config)
+
+	err := eventHandler.Restore(snapshot)
+	if err != nil {
+		return err
+	}
+
+	s.eventHandler = eventHandler
+	s.session.Restore(eventHandler.GetOutcomes())
+
+	return nil
+}
+
+// ListenAndServe listens on the tcp or unix network address and serves the
+// accepted connections.
+func (s *Server) ListenAndServe(network string, address string) error {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	return s.Serve(listener)
+}
+
+// Serve serves the connections accepted by the listener until it is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn handles the lines of the connection until it is closed.
+func (s *Server) ServeConn(conn io.ReadWriteCloser) error {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		response := s.handleLine(line)
+		if response == "" {
+			continue
+		}
+
+		_, err := io.WriteString(conn, response)
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// handleLine returns the lines to send back for the line.
+func (s *Server) handleLine(line string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.applyLine(line)
+	if err != nil {
+		return errorLinePrefix + err.Error() + "\n"
+	}
+
+	response := s.takeOutcomes()
+
+	err = s.writeSnapshot()
+	if err != nil {
+		return response + errorLinePrefix + err.Error() + "\n"
+	}
+
+	return response
+}
+
+func (s *Server) applyLine(line string) error {
+	switch line {
+	case commandOpen:
+		return s.open()
+	case commandClose:
+		return s.close()
+	default:
+		return s.handleEvent(line)
+	}
+}
+
+// appendJournal keeps the accepted line in the journal before it is applied, so
+// the line must be fully checked by then.
+func (s *Server) appendJournal(line string) error {
+	if s.journal == nil {
+		return nil
+	}
+
+	err := s.journal.Append(filehandler.JournalEntry{Sequence: s.journalSequence + 1, Line: line})
+	if err != nil {
+		return err
+	}
+
+	s.journalSequence++
+
+	return nil
+}
+
+func (s *Server) writeSnapshot() error {
+	if s.snapshotFilename == "" {
+		return nil
+	}
+
+	// the journal keeps the lines between the snapshots
+	if s.journal != nil && s.journalSequence-s.snapshotSequence < s.snapshotInterval {
+		return nil
+	}
+
+	err := filehandler.WriteSnapshotFile(s.snapshotFilename, filehandler.SnapshotFile{
+		Snapshot:        s.eventHandler.Snapshot(),
+		JournalSequence: s.journalSequence,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.snapshotSequence = s.journalSequence
+
+	if s.journal == nil {
+		return nil
+	}
+
+	// the snapshot is on the disk by now, and the entries are skipped on
+	// restart anyway, as the snapshot includes them, so a crash before the
+	// truncation loses nothing
+	return s.journal.Truncate()
+}
+
+func (s *Server) open() error {
+	if s.session.IsOpen() {
+		return ErrClubIsAlreadyOpen
+	}
+
+	err := s.appendJournal(commandOpen)
+	if err != nil {
+		return err
+	}
+
+	s.eventHandler = s.newEventHandler(s.config)
+	s.eventHandler.OpenComputerClub()
+
+	return s.session.Open()
+}
+
+func (s *Server) close() error {
+	if !s.session.IsOpen() {
+		return ErrClubIsNotOpen
+	}
+
+	err := s.appendJournal(commandClose)
+	if err != nil {
+		return err
+	}
+
+	s.eventHandler.CloseComputerClub()
+
+	return s.session.Close()
+}
+
+func (s *Server) handleEvent(eventLine string) error {
+	if !s.session.IsOpen() {
+		return ErrClubIsNotOpen
+	}
+
+	err := filehandler.ValidateEventLine(eventLine, s.config)
+	if err != nil {
+		return err
+	}
+
+	event, err := eventhandler.FromEventLine(eventLine)
+	if err != nil {
+		return err
+	}
+
+	event.Time, err = s.session.EventTime(event.Time)
+	if err != nil {
+		return err
+	}
+
+	err = s.appendJournal(eventLine)
+	if err != nil {
+		return err
+	}
+
+	err = s.eventHandler.HandleEvent(event)
+	if err != nil {
+		return err
+	}
+
+	s.session.AddEvent(event.Time)
+
+	return nil
+}
+
+// takeOutcomes renders the outcomes, that are not sent yet, except incoming
+// events.
+func (s *Server) takeOutcomes() string {
+	outcomes := s.session.TakeOutcomes(s.eventHandler.GetOutcomesFrom)
+
+	outcomes = slices.DeleteFunc(outcomes, func(outcome computerclub.Outcome) bool {
+		return outcome.Kind == computerclub.OutcomeKindIncomingEvent
+	})
+
+	return string(computerclub.NewWorkingDayReport(outcomes))
+}
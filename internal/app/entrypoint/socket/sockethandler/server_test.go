@@ -0,0 +1,225 @@
+package sockethandler
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/vaberof/yadro-test-task/internal/app/entrypoint/event/eventhandler"
+	"github.com/vaberof/yadro-test-task/internal/domain/computerclub"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServeConn(t *testing.T) {
+	openingTime, _ := xtime.ParseHoursMinutesFromString("09:00")
+	closingTime, _ := xtime.ParseHoursMinutesFromString("19:00")
+
+	config := &computerclub.Config{
+		TablesCount:  1,
+		OpeningTime:  openingTime,
+		ClosingTime:  closingTime,
+		PricePerHour: 10,
+	}
+
+	server := NewServer(config, func(config *computerclub.Config) eventhandler.Handler {
+		return eventhandler.NewHandler(computerclub.NewComputerClub(config))
+	})
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	go server.ServeConn(serverConn)
+
+	reader := bufio.NewReader(clientConn)
+
+	requests := []struct {
+		line          string
+		expectedLines []string
+	}{
+		{"09:10 1 client1", []string{"ERR club is not open"}},
+		{"open", []string{"09:00"}},
+		{"08:50 1 client1", []string{"08:50 13 NotOpenYet"}},
+		{"09:10 1 client1", nil},
+		{"09:20 2 client1 1", nil},
+		{"09:30 1 client2", nil},
+		{"09:40 3 client2", nil},
+		{"09:30 1 client3", []string{"ERR event is earlier than the previous one"}},
+		{"10:00 2 client2 2", []string{"ERR invalid format of table number"}},
+		{"10:00 1 BOB!", []string{"ERR invalid format of client name"}},
+		{"10:00 42 bob", []string{"ERR invalid format of event"}},
+		{"10:10 4 client1", []string{"10:10 12 client2 1"}},
+		{"close", []string{"19:00 11 client2", "19:00", "1 100 09:40"}},
+		{"close", []string{"ERR club is not open"}},
+	}
+
+	for _, request := range requests {
+		_, err := io.WriteString(clientConn, request.line+"\n")
+		if err != nil {
+			t.Fatalf("TestServeConn: %s", err.Error())
+		}
+
+		// the server answers nothing, if there is nothing to report
+		if request.expectedLines == nil {
+			continue
+		}
+
+		for _, expectedLine := range request.expectedLines {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("TestServeConn: %s", err.Error())
+			}
+
+			if line != expectedLine+"\n" {
+				err = fmt.Errorf("expected line '%s' for '%s', got: '%s'", expectedLine, request.line, line)
+				t.Fatalf("TestServeConn: %s", err.Error())
+			}
+		}
+	}
+}
+
+func TestUseSnapshotFile(t *testing.T) {
+	openingTime, _ := xtime.ParseHoursMinutesFromString("09:00")
+	closingTime, _ := xtime.ParseHoursMinutesFromString("19:00")
+
+	config := &computerclub.Config{
+		TablesCount:  1,
+		OpeningTime:  openingTime,
+		ClosingTime:  closingTime,
+		PricePerHour: 10,
+	}
+
+	newEventHandler := func(config *computerclub.Config) eventhandler.Handler {
+		return eventhandler.NewHandler(computerclub.NewComputerClub(config))
+	}
+
+	snapshotFilename := filepath.Join(t.TempDir(), "club.json")
+
+	server := NewServer(config, newEventHandler)
+
+	err := server.UseSnapshotFile(snapshotFilename)
+	if err != nil {
+		t.Fatalf("TestUseSnapshotFile: %s", err.Error())
+	}
+
+	for _, line := range []string{"open", "09:10 1 client1", "09:20 2 client1 1", "09:30 1 client2", "09:40 3 client2"} {
+		server.handleLine(line)
+	}
+
+	// the restarted server continues the day from the snapshot file
+	restartedServer := NewServer(config, newEventHandler)
+
+	err = restartedServer.UseSnapshotFile(snapshotFilename)
+	if err != nil {
+		t.Fatalf("TestUseSnapshotFile: %s", err.Error())
+	}
+
+	requests := []struct {
+		line             string
+		expectedResponse string
+	}{
+		{"open", "ERR club is already open\n"},
+		{"09:30 1 client3", "ERR event is earlier than the previous one\n"},
+		{"10:10 4 client1", "10:10 12 client2 1\n"},
+		{"close", "19:00 11 client2\n19:00\n1 100 09:40\n"},
+	}
+
+	for _, request := range requests {
+		response := restartedServer.handleLine(request.line)
+
+		if response != request.expectedResponse {
+			err = fmt.Errorf("expected response '%s' for '%s', got: '%s'", request.expectedResponse, request.line, response)
+			t.Fatalf("TestUseSnapshotFile: %s", err.Error())
+		}
+	}
+}
+
+func TestUseJournalFile(t *testing.T) {
+	openingTime, _ := xtime.ParseHoursMinutesFromString("09:00")
+	closingTime, _ := xtime.ParseHoursMinutesFromString("19:00")
+
+	config := &computerclub.Config{
+		TablesCount:  1,
+		OpeningTime:  openingTime,
+		ClosingTime:  closingTime,
+		PricePerHour: 10,
+	}
+
+	newEventHandler := func(config *computerclub.Config) eventhandler.Handler {
+		return eventhandler.NewHandler(computerclub.NewComputerClub(config))
+	}
+
+	snapshotFilename := filepath.Join(t.TempDir(), "club.json")
+	journalFilename := filepath.Join(t.TempDir(), "club.journal")
+
+	newServer := func() *Server {
+		server := NewServer(config, newEventHandler)
+
+		err := server.UseSnapshotFile(snapshotFilename)
+		if err != nil {
+			t.Fatalf("TestUseJournalFile: %s", err.Error())
+		}
+
+		err = server.UseJournalFile(journalFilename, 3)
+		if err != nil {
+			t.Fatalf("TestUseJournalFile: %s", err.Error())
+		}
+
+		return server
+	}
+
+	server := newServer()
+
+	// the snapshot is written after the third line, the journal keeps the rest,
+	// except the rejected lines
+	for _, line := range []string{"open", "09:10 1 client1", "09:20 2 client1 1", "10:01 42 bob", "09:30 1 client2", "09:40 3 client2"} {
+		server.handleLine(line)
+	}
+
+	journalContent, err := os.ReadFile(journalFilename)
+	if err != nil {
+		t.Fatalf("TestUseJournalFile: %s", err.Error())
+	}
+
+	expectedJournalContent := "4 09:30 1 client2\n5 09:40 3 client2\n"
+
+	if string(journalContent) != expectedJournalContent {
+		err = fmt.Errorf("expected journal: '%s', got: '%s'", expectedJournalContent, journalContent)
+		t.Fatalf("TestUseJournalFile: %s", err.Error())
+	}
+
+	// the server crashed in the middle of appending the next line
+	journalFile, err := os.OpenFile(journalFilename, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("TestUseJournalFile: %s", err.Error())
+	}
+
+	_, err = io.WriteString(journalFile, "6 10:0")
+	journalFile.Close()
+	if err != nil {
+		t.Fatalf("TestUseJournalFile: %s", err.Error())
+	}
+
+	restartedServer := newServer()
+
+	requests := []struct {
+		line             string
+		expectedResponse string
+	}{
+		{"open", "ERR club is already open\n"},
+		{"09:30 1 client3", "ERR event is earlier than the previous one\n"},
+		{"10:10 4 client1", "10:10 12 client2 1\n"},
+		{"close", "19:00 11 client2\n19:00\n1 100 09:40\n"},
+	}
+
+	for _, request := range requests {
+		response := restartedServer.handleLine(request.line)
+
+		if response != request.expectedResponse {
+			err = fmt.Errorf("expected response '%s' for '%s', got: '%s'", request.expectedResponse, request.line, response)
+			t.Fatalf("TestUseJournalFile: %s", err.Error())
+		}
+	}
+}
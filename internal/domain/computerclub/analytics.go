@@ -0,0 +1,29 @@
+package computerclub
+
+import "time"
+
+// QueueStats sum up the activity of the queue during the day, the waits are
+// summed up by WaitStats.
+type QueueStats struct {
+	// TurnedAway is the number of clients, who did not fit in the queue and
+	// left the club or were referred to the partner club
+	TurnedAway int
+	// SentToWaitingArea is the number of clients, who did not fit in the queue
+	// and were sent to the waiting area
+	SentToWaitingArea int
+	// Rejected is the number of clients, who could not wait, as there was a
+	// free table
+	Rejected int
+	// PeakLength is the longest the queue has been, PeakTime is when it first
+	// became that long
+	PeakLength int
+	PeakTime   time.Time
+}
+
+// updateQueuePeak keeps the length of the queue, if it is the longest so far.
+func (c *computerClubServiceImpl) updateQueuePeak(eventTime time.Time) {
+	if length := c.clientQueue.Len(); length > c.queueStats.PeakLength {
+		c.queueStats.PeakLength = length
+		c.queueStats.PeakTime = eventTime
+	}
+}
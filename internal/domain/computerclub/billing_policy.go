@@ -0,0 +1,45 @@
+package computerclub
+
+import "time"
+
+// BillingPolicy decides how much of a table session the client is charged for.
+type BillingPolicy interface {
+	BillableTime(usageTime time.Duration) time.Duration
+}
+
+type blockBillingPolicy struct {
+	blockSize   time.Duration
+	gracePeriod time.Duration
+}
+
+// NewHourlyBillingPolicy charges every started hour in full.
+func NewHourlyBillingPolicy() BillingPolicy {
+	return &blockBillingPolicy{blockSize: time.Hour}
+}
+
+// NewPerMinuteBillingPolicy charges exactly the minutes spent at the table.
+func NewPerMinuteBillingPolicy() BillingPolicy {
+	return &blockBillingPolicy{blockSize: time.Minute}
+}
+
+// NewBlockBillingPolicy charges every started block of the given size in full.
+func NewBlockBillingPolicy(blockSize time.Duration) BillingPolicy {
+	return &blockBillingPolicy{blockSize: blockSize}
+}
+
+// NewGraceBillingPolicy charges every started hour in full, except the hours
+// that were used no longer than the grace period.
+func NewGraceBillingPolicy(gracePeriod time.Duration) BillingPolicy {
+	return &blockBillingPolicy{blockSize: time.Hour, gracePeriod: gracePeriod}
+}
+
+func (b *blockBillingPolicy) BillableTime(usageTime time.Duration) time.Duration {
+	blocks := usageTime / b.blockSize
+	remainder := usageTime % b.blockSize
+
+	if remainder > b.gracePeriod {
+		blocks++
+	}
+
+	return blocks * b.blockSize
+}
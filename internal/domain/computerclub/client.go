@@ -1,5 +1,7 @@
 package computerclub
 
+import "time"
+
 type ClientName string
 
 func (clientName *ClientName) String() string {
@@ -10,4 +12,8 @@ type Client struct {
 	Name        ClientName
 	State       uint8
 	BusyTableId TableId
+	// PausedSince is set, while the client is paused
+	PausedSince time.Time
+	// WaitingSince is set, while the client is waiting
+	WaitingSince time.Time
 }
@@ -1,34 +1,144 @@
 package computerclub
 
+import (
+	"slices"
+	"time"
+)
+
+type queueEntry struct {
+	client          *Client
+	membershipLevel int
+}
+
+// ClientQueue keeps the waiting clients in the order they started waiting, the
+// queue policy picks the next one to take a table.
 type ClientQueue struct {
-	queue   []*Client
-	maxSize int
+	entries []queueEntry
+	// frontSize is the number of clients at the head of the queue, that go
+	// first regardless of the policy
+	frontSize int
+	maxSize   int
+	policy    QueuePolicy
 }
 
-func NewClientQueue(maxSize int) *ClientQueue {
+func NewClientQueue(maxSize int, policy QueuePolicy) *ClientQueue {
 	return &ClientQueue{
-		queue:   make([]*Client, 0, maxSize),
+		entries: make([]queueEntry, 0, maxSize),
 		maxSize: maxSize,
+		policy:  policy,
 	}
 }
 
-func (c *ClientQueue) Push(client *Client) {
-	c.queue = append(c.queue, client)
+func (c *ClientQueue) Push(client *Client, membershipLevel int) {
+	c.entries = append(c.entries, queueEntry{client: client, membershipLevel: membershipLevel})
+}
+
+// PushFront puts the client before all waiting clients, even if the queue is
+// full. The client goes first regardless of the policy.
+func (c *ClientQueue) PushFront(client *Client, membershipLevel int) {
+	c.entries = slices.Insert(c.entries, 0, queueEntry{client: client, membershipLevel: membershipLevel})
+	c.frontSize++
 }
 
-func (c *ClientQueue) Pop() *Client {
-	var client *Client
-	if len(c.queue) > 0 {
-		client = c.queue[0]
-		c.queue = c.queue[1:]
+// Pop takes the client, that goes next at the given time, out of the queue.
+func (c *ClientQueue) Pop(t time.Time) *Client {
+	if len(c.entries) == 0 {
+		return nil
 	}
+
+	i := c.next(t)
+
+	client := c.entries[i].client
+	c.removeAt(i)
+
 	return client
 }
 
+// Remove takes the client out of the queue and reports whether the client was
+// waiting.
+func (c *ClientQueue) Remove(clientName ClientName) bool {
+	for i, entry := range c.entries {
+		if entry.client.Name == clientName {
+			c.removeAt(i)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ClientQueue) Len() int {
+	return len(c.entries)
+}
+
 func (c *ClientQueue) IsEmpty() bool {
-	return len(c.queue) == 0
+	return len(c.entries) == 0
 }
 
 func (c *ClientQueue) IsFull() bool {
-	return len(c.queue) == c.maxSize
+	return len(c.entries) >= c.maxSize
+}
+
+// ClientNames returns the waiting clients in the order they are kept, the
+// clients at the head go first.
+func (c *ClientQueue) ClientNames() []ClientName {
+	clientNames := make([]ClientName, 0, len(c.entries))
+	for _, entry := range c.entries {
+		clientNames = append(clientNames, entry.client.Name)
+	}
+	return clientNames
+}
+
+// FrontSize returns the number of clients at the head of the queue, that go
+// first regardless of the policy.
+func (c *ClientQueue) FrontSize() int {
+	return c.frontSize
+}
+
+// OrderedClientNames returns the waiting clients in the order they take tables,
+// if all of them did at the given time.
+func (c *ClientQueue) OrderedClientNames(t time.Time) []ClientName {
+	entries := slices.Clone(c.entries)
+	frontSize := c.frontSize
+
+	clientNames := make([]ClientName, 0, len(entries))
+
+	for len(entries) > 0 {
+		i := 0
+		if frontSize == 0 {
+			i = c.policy.Next(waitingClients(entries), t)
+		} else {
+			frontSize--
+		}
+
+		clientNames = append(clientNames, entries[i].client.Name)
+		entries = slices.Delete(entries, i, i+1)
+	}
+
+	return clientNames
+}
+
+func (c *ClientQueue) next(t time.Time) int {
+	if c.frontSize > 0 {
+		return 0
+	}
+	return c.policy.Next(waitingClients(c.entries), t)
+}
+
+func (c *ClientQueue) removeAt(i int) {
+	c.entries = slices.Delete(c.entries, i, i+1)
+	if i < c.frontSize {
+		c.frontSize--
+	}
+}
+
+func waitingClients(entries []queueEntry) []WaitingClient {
+	waitingClients := make([]WaitingClient, 0, len(entries))
+	for _, entry := range entries {
+		waitingClients = append(waitingClients, WaitingClient{
+			Name:            entry.client.Name,
+			MembershipLevel: entry.membershipLevel,
+			WaitingSince:    entry.client.WaitingSince,
+		})
+	}
+	return waitingClients
 }
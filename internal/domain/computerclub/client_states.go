@@ -5,4 +5,6 @@ const (
 	StateClientTookPlace
 	StateClientIsWaiting
 	StateClientHasLeft
+	StateClientIsPaused
+	StateClientIsInWaitingArea
 )
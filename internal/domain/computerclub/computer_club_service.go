@@ -2,7 +2,9 @@ package computerclub
 
 import (
 	"errors"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
 	"slices"
+	"sync"
 	"time"
 )
 
@@ -11,12 +13,22 @@ const (
 	IncomingEventClientTookPlace
 	IncomingEventClientWaiting
 	IncomingEventClientLeft
+	IncomingEventClientReserved
+	IncomingEventTableOutOfService
+	IncomingEventTableBackInService
+	IncomingEventClientPaused
+	IncomingEventClientResumed
 )
 
 const (
 	OutgoingEventClientLeft uint8 = iota + 11
 	OutgoingEventClientTookPlace
 	OutgoingEventError
+	OutgoingEventReservationExpired
+	OutgoingEventClientWaiting
+	OutgoingEventPauseExpired
+	OutgoingEventClientInWaitingArea
+	OutgoingEventClientReferred
 )
 
 var (
@@ -26,80 +38,261 @@ var (
 	ErrPlaceIsBusy      = errors.New("PlaceIsBusy")
 	ErrICanWaitNoLonger = errors.New("ICanWaitNoLonger!")
 
+	ErrClientIsNotSeated = errors.New("ClientIsNotSeated")
+	ErrClientIsNotPaused = errors.New("ClientIsNotPaused")
+
+	ErrClientIsAlreadyWaiting = errors.New("ClientIsAlreadyWaiting")
+
+	ErrTableIsOutOfService = errors.New("TableIsOutOfService")
+	ErrTableIsInService    = errors.New("TableIsInService")
+
+	ErrInvalidReservationTime = errors.New("InvalidReservationTime")
+
 	ErrQueueIsFull = errors.New("Queue is full")
 )
 
 type ComputerClubService interface {
 	Open()
+	OpenOn(date time.Time)
 	ProcessEventClientArrived(eventTime time.Time, clientName ClientName) error
 	ProcessEventClientTookPlace(eventTime time.Time, clientName ClientName, tableId TableId) error
 	ProcessEventClientWaiting(eventTime time.Time, clientName ClientName) error
 	ProcessEventClientLeft(eventTime time.Time, clientName ClientName) error
+	ProcessEventClientReserved(eventTime time.Time, clientName ClientName, tableId TableId, startTime time.Time, endTime time.Time) error
+	ProcessEventTableOutOfService(eventTime time.Time, staffName StaffName, tableId TableId) error
+	ProcessEventTableBackInService(eventTime time.Time, staffName StaffName, tableId TableId) error
+	ProcessEventClientPaused(eventTime time.Time, clientName ClientName) error
+	ProcessEventClientResumed(eventTime time.Time, clientName ClientName) error
+	AdvanceTo(t time.Time)
 	Close()
+	GetOutcomes() []Outcome
+	GetOutcomesFrom(index int) []Outcome
+	TakeOutcomes() []Outcome
 	GetWorkingDayReport() WorkingDayReport
+	GetPeriodOutcomes() []Outcome
+	GetPeriodReport() WorkingDayReport
+	GetOccupancy() []TableOccupancy
+	GetClients() []Client
+	GetQueue(at time.Time) []QueuedClient
+	GetRunningRevenue(at time.Time) int
+	Snapshot() Snapshot
+	Restore(snapshot Snapshot) error
 }
 
+// Config describes the computer club. ClosingTime of a club, that is open past
+// midnight, is on the day after OpeningTime.
 type Config struct {
 	TablesCount  int
 	OpeningTime  time.Time
 	ClosingTime  time.Time
 	PricePerHour int
+
+	// Tariffs override PricePerHour during their time ranges
+	Tariffs []Tariff
+
+	// Categories list the tables, that are not billed at PricePerHour
+	Categories []TableCategory
+
+	// BillingPolicy defaults to hourly billing when not set
+	BillingPolicy BillingPolicy
+
+	// MaxPauseDuration is the longest pause, after which the seat of the client
+	// is released, zero means no limit
+	MaxPauseDuration time.Duration
+
+	// QueuePolicy defaults to the order of waiting when not set
+	QueuePolicy QueuePolicy
+
+	// Memberships hold the membership levels of the clients, that have one
+	Memberships map[ClientName]int
+
+	// QueueSize is the number of clients, that may wait in the queue, it
+	// defaults to one more than the number of tables when not set
+	QueueSize int
+
+	// OverflowPolicy defaults to turning the client away
+	OverflowPolicy OverflowPolicy
+
+	// MaxWaitDuration is the longest wait, after which the client gives up and
+	// leaves, zero means no limit
+	MaxWaitDuration time.Duration
+
+	// QueueAnalytics adds the queue and wait statistics to the report of the day
+	QueueAnalytics bool
 }
 
+// computerClubServiceImpl is safe for concurrent use. Calls are applied one at
+// a time in the order they acquire the service, so events with equal time are
+// handled and reported in the order of their calls, and the outcomes of a call
+// are never interleaved with the outcomes of another one.
 type computerClubServiceImpl struct {
-	tablesCount  int
-	openingTime  time.Time
-	closingTime  time.Time
-	pricePerHour int
+	mu sync.Mutex
+
+	tablesCount int
+	openingTime time.Time
+	closingTime time.Time
+	// categories are ordered as in config, the standard category goes first
+	categories      []string
+	tariffSchedules map[string]*tariffSchedule
+	billingPolicy   BillingPolicy
+
+	maxPauseDuration time.Duration
+	queuePolicy      QueuePolicy
+	memberships      map[ClientName]int
+	queueSize        int
+	overflowPolicy   OverflowPolicy
+	maxWaitDuration  time.Duration
+	queueAnalytics   bool
 
 	clients map[ClientName]Client
 	tables  map[TableId]Table
 
 	clientQueue *ClientQueue
-
-	// buf contains all output for the day
-	buf WorkingDayReport
+	// waitingArea lists the clients, that did not fit in the queue, in the
+	// order they came
+	waitingArea []ClientName
+	waitStats   WaitStats
+	queueStats  QueueStats
+
+	// reservations are not taken yet, noShows have expired during the day
+	reservations []Reservation
+	noShows      []Reservation
+
+	// outcomes contain everything that happened during the day
+	outcomes Outcomes
+
+	// periodTables sum up the tables of all closed days
+	periodTables    map[TableId]Table
+	periodStartDate time.Time
+	periodEndDate   time.Time
 }
 
-const startBufSize = 512
+const startOutcomesSize = 64
 
 const minTablesCount = 1
 
+// IsOvernight reports whether the club is open past midnight.
+func (c *Config) IsOvernight() bool {
+	return c.ClosingTime.Before(c.OpeningTime) || c.ClosingTime.Day() != c.OpeningTime.Day()
+}
+
+// IsAfterMidnight reports whether the HH:MM time of an event belongs to the next
+// day of the working day, that is the club is open past midnight and the time
+// is not after the closing time. Other times before the opening time belong to
+// the opening day.
+func (c *Config) IsAfterMidnight(clock time.Time) bool {
+	if !c.IsOvernight() {
+		return false
+	}
+	return !xtime.OnDate(c.OpeningTime, clock).After(xtime.OnDate(c.OpeningTime, c.ClosingTime))
+}
+
+// NewComputerClub creates the service for a working day. Event times of a club,
+// that is open past midnight, must be on the next day after midnight.
 func NewComputerClub(config *Config) ComputerClubService {
 	tables := make(map[TableId]Table)
 
+	billingPolicy := config.BillingPolicy
+	if billingPolicy == nil {
+		billingPolicy = NewHourlyBillingPolicy()
+	}
+
+	queuePolicy := config.QueuePolicy
+	if queuePolicy == nil {
+		queuePolicy = NewFIFOQueuePolicy()
+	}
+
+	queueSize := config.QueueSize
+	if queueSize == 0 {
+		queueSize = config.TablesCount + 1
+	}
+
 	for tableId := TableId(minTablesCount); tableId <= TableId(config.TablesCount); tableId++ {
 		tables[tableId] = Table{
-			Id:    tableId,
-			State: StateTableIsFree,
+			Id:       tableId,
+			Category: StandardTableCategory,
+			State:    StateTableIsFree,
 		}
 	}
 
+	categories := []string{StandardTableCategory}
+
+	tariffSchedules := map[string]*tariffSchedule{
+		StandardTableCategory: newTariffSchedule(config.PricePerHour, getCategoryTariffs(config.Tariffs, StandardTableCategory)),
+	}
+
+	for _, category := range config.Categories {
+		for _, tableId := range category.TableIds {
+			table := tables[tableId]
+			table.Category = category.Name
+			tables[tableId] = table
+		}
+
+		categories = append(categories, category.Name)
+		tariffSchedules[category.Name] = newTariffSchedule(category.PricePerHour, getCategoryTariffs(config.Tariffs, category.Name))
+	}
+
+	closingTime := config.ClosingTime
+	if closingTime.Before(config.OpeningTime) {
+		closingTime = closingTime.Add(24 * time.Hour)
+	}
+
 	computerClub := &computerClubServiceImpl{
-		tablesCount:  config.TablesCount,
-		openingTime:  config.OpeningTime,
-		closingTime:  config.ClosingTime,
-		pricePerHour: config.PricePerHour,
-		clients:      make(map[ClientName]Client),
-		tables:       tables,
-		clientQueue:  NewClientQueue(config.TablesCount + 1),
-		buf:          make([]byte, 0, startBufSize),
+		tablesCount:      config.TablesCount,
+		openingTime:      config.OpeningTime,
+		closingTime:      closingTime,
+		categories:       categories,
+		tariffSchedules:  tariffSchedules,
+		billingPolicy:    billingPolicy,
+		maxPauseDuration: config.MaxPauseDuration,
+		queuePolicy:      queuePolicy,
+		memberships:      config.Memberships,
+		queueSize:        queueSize,
+		overflowPolicy:   config.OverflowPolicy,
+		maxWaitDuration:  config.MaxWaitDuration,
+		queueAnalytics:   config.QueueAnalytics,
+		clients:          make(map[ClientName]Client),
+		tables:           tables,
+		outcomes:         make(Outcomes, 0, startOutcomesSize),
+		periodTables:     make(map[TableId]Table),
 	}
 
+	computerClub.clientQueue = computerClub.newClientQueue()
+
 	return computerClub
 }
 
+func getCategoryTariffs(tariffs []Tariff, category string) []Tariff {
+	var categoryTariffs []Tariff
+	for _, tariff := range tariffs {
+		tariffCategory := tariff.Category
+		if tariffCategory == "" {
+			tariffCategory = StandardTableCategory
+		}
+
+		if tariffCategory == category {
+			categoryTariffs = append(categoryTariffs, tariff)
+		}
+	}
+	return categoryTariffs
+}
+
 func (c *computerClubServiceImpl) ProcessEventClientArrived(eventTime time.Time, clientName ClientName) error {
-	c.buf.writeEvent(eventTime, IncomingEventClientArrived, clientName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventClientArrived, clientName, 0)
 
 	if c.isClientInComputerClub(clientName) {
-		c.buf.writeEventError(eventTime, ErrYouShallNotPass)
+		c.outcomes.addEventError(eventTime, ErrYouShallNotPass)
 
 		return ErrYouShallNotPass
 	}
 
 	if c.isNonWorkingHours(eventTime) {
-		c.buf.writeEventError(eventTime, ErrNotOpenYet)
+		c.outcomes.addEventError(eventTime, ErrNotOpenYet)
 
 		return ErrNotOpenYet
 	}
@@ -110,79 +303,107 @@ func (c *computerClubServiceImpl) ProcessEventClientArrived(eventTime time.Time,
 }
 
 func (c *computerClubServiceImpl) ProcessEventClientTookPlace(eventTime time.Time, clientName ClientName, tableId TableId) error {
-	c.buf.writeEventWithTableId(eventTime, IncomingEventClientTookPlace, clientName, tableId)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventClientTookPlace, clientName, tableId)
 
-	if c.isBusyTable(tableId) {
-		c.buf.writeEventError(eventTime, ErrPlaceIsBusy)
+	if c.isBusyTable(tableId) || c.isReservedFor(tableId, eventTime, clientName) {
+		c.outcomes.addEventError(eventTime, ErrPlaceIsBusy)
+
+		// the client, who finds the reserved table taken, is not a no-show
+		if reservation, ok := c.activeReservation(tableId, eventTime); ok && reservation.ClientName == clientName && c.isClientInComputerClub(clientName) {
+			c.claimReservation(tableId, clientName)
+		}
 
 		return ErrPlaceIsBusy
 	}
 
 	if !c.isClientInComputerClub(clientName) {
-		c.buf.writeEventError(eventTime, ErrClientUnknown)
+		c.outcomes.addEventError(eventTime, ErrClientUnknown)
 
 		return ErrClientUnknown
 	}
 
 	client := c.clients[clientName]
 
-	if client.State == StateClientTookPlace {
-		busyTableId := client.BusyTableId
-		c.freeTable(busyTableId, eventTime)
-
-		if !c.clientQueue.IsEmpty() {
-			clientFromQueue := c.clientQueue.Pop()
-			c.takeTable(busyTableId, eventTime, clientFromQueue)
-			c.buf.writeEventWithTableId(eventTime, OutgoingEventClientTookPlace, clientFromQueue.Name, busyTableId)
-		}
+	switch client.State {
+	case StateClientTookPlace, StateClientIsPaused:
+		c.leaveTable(&client, eventTime)
+	case StateClientIsWaiting:
+		c.clientQueue.Remove(client.Name)
+		c.fillQueueFromWaitingArea(eventTime)
+	case StateClientIsInWaitingArea:
+		c.removeFromWaitingArea(client.Name)
 	}
 
+	c.claimReservation(tableId, clientName)
 	c.takeTable(tableId, eventTime, &client)
 
 	return nil
 }
 
 func (c *computerClubServiceImpl) ProcessEventClientWaiting(eventTime time.Time, clientName ClientName) error {
-	c.buf.writeEvent(eventTime, IncomingEventClientWaiting, clientName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventClientWaiting, clientName, 0)
 
-	if c.isThereFreeTable() {
-		c.buf.writeEventError(eventTime, ErrICanWaitNoLonger)
+	if c.isClientWaiting(clientName) {
+		c.outcomes.addEventError(eventTime, ErrClientIsAlreadyWaiting)
+
+		return ErrClientIsAlreadyWaiting
+	}
+
+	if c.isThereFreeTable(eventTime, clientName) {
+		c.outcomes.addEventError(eventTime, ErrICanWaitNoLonger)
+		c.queueStats.Rejected++
 
 		return ErrICanWaitNoLonger
 	}
 
 	if c.clientQueue.IsFull() {
-		c.deleteClient(clientName)
-
-		c.buf.writeEvent(eventTime, OutgoingEventClientLeft, clientName)
+		c.overflowQueue(clientName, eventTime)
 
 		return ErrQueueIsFull
 	}
 
-	c.addClientToQueue(clientName)
+	c.addClientToQueue(clientName, eventTime)
 
 	return nil
 }
 
 func (c *computerClubServiceImpl) ProcessEventClientLeft(eventTime time.Time, clientName ClientName) error {
-	c.buf.writeEvent(eventTime, IncomingEventClientLeft, clientName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventClientLeft, clientName, 0)
 
 	if !c.isClientInComputerClub(clientName) {
-		c.buf.writeEventError(eventTime, ErrClientUnknown)
+		c.outcomes.addEventError(eventTime, ErrClientUnknown)
 
 		return ErrClientUnknown
 	}
 
 	client := c.clients[clientName]
-	busyTableId := client.BusyTableId
-
-	c.freeTable(busyTableId, eventTime)
-
-	if !c.clientQueue.IsEmpty() {
-		clientFromQueue := c.clientQueue.Pop()
-		c.takeTable(busyTableId, eventTime, clientFromQueue)
 
-		c.buf.writeEventWithTableId(eventTime, OutgoingEventClientTookPlace, clientFromQueue.Name, busyTableId)
+	switch client.State {
+	case StateClientTookPlace, StateClientIsPaused:
+		c.leaveTable(&client, eventTime)
+	case StateClientIsWaiting:
+		// the client only gives up the place in the queue
+		c.stopWaiting(&client, eventTime)
+		c.clientQueue.Remove(client.Name)
+		c.fillQueueFromWaitingArea(eventTime)
+	case StateClientIsInWaitingArea:
+		c.stopWaiting(&client, eventTime)
+		c.removeFromWaitingArea(client.Name)
 	}
 
 	c.deleteClient(client.Name)
@@ -191,27 +412,175 @@ func (c *computerClubServiceImpl) ProcessEventClientLeft(eventTime time.Time, cl
 }
 
 func (c *computerClubServiceImpl) Open() {
-	c.buf.writeTime(c.openingTime)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.open()
+}
+
+func (c *computerClubServiceImpl) open() {
+	c.outcomes.addOpening(c.openingTime)
+}
+
+// OpenOn starts a new working day on the given date. The tables, clients and
+// report of the previous day are reset, so it must be closed before.
+func (c *computerClubServiceImpl) OpenOn(date time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	workingDayDuration := c.closingTime.Sub(c.openingTime)
+
+	c.openingTime = xtime.OnDate(date, c.openingTime)
+	c.closingTime = c.openingTime.Add(workingDayDuration)
+
+	for tableId, table := range c.tables {
+		newTable := Table{
+			Id:       tableId,
+			Category: table.Category,
+			State:    StateTableIsFree,
+		}
+
+		// the table stays out of service since the opening of the day
+		if table.State == StateTableIsOutOfService {
+			newTable.State = StateTableIsOutOfService
+			newTable.OutOfServiceSince = c.openingTime
+		}
+
+		c.tables[tableId] = newTable
+	}
+
+	c.clients = make(map[ClientName]Client)
+	c.clientQueue = c.newClientQueue()
+	c.waitingArea = nil
+	c.waitStats = WaitStats{}
+	c.queueStats = QueueStats{}
+	c.reservations = nil
+	c.noShows = nil
+	c.outcomes = make(Outcomes, 0, startOutcomesSize)
+
+	if c.periodStartDate.IsZero() {
+		c.periodStartDate = xtime.StartOfDay(date)
+	}
+	c.periodEndDate = xtime.StartOfDay(date)
+
+	c.open()
+}
+
+// AdvanceTo handles everything, that happens by itself by the given time,
+// without an incoming event.
+func (c *computerClubServiceImpl) AdvanceTo(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(t)
 }
 
 func (c *computerClubServiceImpl) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(c.closingTime)
+
 	clientNames := c.getRemainingClientNames()
 	slices.Sort(clientNames)
 
 	for _, clientName := range clientNames {
-		c.buf.writeEvent(c.closingTime, OutgoingEventClientLeft, clientName)
+		c.outcomes.addOutgoingEvent(c.closingTime, OutgoingEventClientLeft, clientName, 0)
+	}
+
+	c.outcomes.addClosing(c.closingTime)
+
+	for tableId, table := range c.tables {
+		if table.State == StateTableIsOutOfService {
+			table.DowntimePerDay += c.closingTime.Sub(table.OutOfServiceSince)
+			c.tables[tableId] = table
+		}
+	}
+
+	c.outcomes.addTablesSummary(c.tablesCount, c.tables, c.categories, c.noShows)
+
+	// the statistics are only of interest, when clients may give up waiting
+	if c.maxWaitDuration > 0 {
+		c.outcomes.addWaitSummary(c.waitStats)
+	}
+
+	if c.queueAnalytics {
+		c.outcomes.addQueueAnalytics(c.waitStats, c.queueStats)
+	}
+
+	for tableId, table := range c.tables {
+		periodTable := c.periodTables[tableId]
+		periodTable.Id = tableId
+		periodTable.Category = table.Category
+		periodTable.Profit += table.Profit
+		periodTable.UsageTimePerDay += table.UsageTimePerDay
+		periodTable.DowntimePerDay += table.DowntimePerDay
+		c.periodTables[tableId] = periodTable
 	}
+}
+
+func (c *computerClubServiceImpl) GetOutcomes() []Outcome {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	c.buf.writeTime(c.closingTime)
+	return slices.Clone(c.outcomes)
+}
 
-	for tableId := TableId(minTablesCount); tableId <= TableId(c.tablesCount); tableId++ {
-		table := c.tables[tableId]
-		c.buf.writeTableReport(tableId, table.Profit, table.usageTimePerDayString())
+// GetOutcomesFrom returns the outcomes of the day starting from the index, so
+// that outcomes can be reported as they happen.
+func (c *computerClubServiceImpl) GetOutcomesFrom(index int) []Outcome {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if index >= len(c.outcomes) {
+		return nil
 	}
+	return slices.Clone(c.outcomes[index:])
+}
+
+// TakeOutcomes returns the outcomes of the day, that are not taken yet, and
+// forgets them, so that the day reported as it happens is not kept in memory.
+// The outcomes taken are missing from GetOutcomes and the working day report.
+func (c *computerClubServiceImpl) TakeOutcomes() []Outcome {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	outcomes := c.outcomes
+	c.outcomes = nil
+
+	return outcomes
 }
 
 func (c *computerClubServiceImpl) GetWorkingDayReport() WorkingDayReport {
-	return c.buf
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return NewWorkingDayReport(c.outcomes)
+}
+
+// GetPeriodOutcomes sums up the tables over all closed days, since the first
+// day opened with OpenOn.
+func (c *computerClubServiceImpl) GetPeriodOutcomes() []Outcome {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.getPeriodOutcomes()
+}
+
+func (c *computerClubServiceImpl) getPeriodOutcomes() Outcomes {
+	var periodOutcomes Outcomes
+
+	periodOutcomes.addPeriod(c.periodStartDate, c.periodEndDate)
+	periodOutcomes.addTablesSummary(c.tablesCount, c.periodTables, c.categories, nil)
+
+	return periodOutcomes
+}
+
+func (c *computerClubServiceImpl) GetPeriodReport() WorkingDayReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return NewWorkingDayReport(c.getPeriodOutcomes())
 }
 
 func (c *computerClubServiceImpl) getRemainingClientNames() []ClientName {
@@ -223,6 +592,10 @@ func (c *computerClubServiceImpl) getRemainingClientNames() []ClientName {
 		if client.State == StateClientTookPlace {
 			busyTableId := client.BusyTableId
 			c.freeTable(busyTableId, c.closingTime)
+		} else if client.State == StateClientIsPaused {
+			c.releaseSeat(&client)
+		} else {
+			c.stopWaiting(&client, c.closingTime)
 		}
 
 		c.deleteClient(client.Name)
@@ -239,23 +612,105 @@ func (c *computerClubServiceImpl) takeTable(tableId TableId, startTime time.Time
 
 	client.State = StateClientTookPlace
 	client.BusyTableId = tableId
+	c.stopWaiting(client, startTime)
 	c.clients[client.Name] = *client
 }
 
+// advanceTo handles everything, that happens by itself by the given time, in
+// the order it happens: reservations expire, pauses and waits run out. At the
+// same time reservations go before pauses and pauses go before waits. Nothing
+// happens by itself past the closing time, the clients left then leave on close.
+func (c *computerClubServiceImpl) advanceTo(t time.Time) {
+	if t.After(c.closingTime) {
+		t = c.closingTime
+	}
+
+	for {
+		var expire func()
+		var expireTime time.Time
+
+		if reservationIndex, ok := c.nextExpiredReservation(t); ok {
+			expire = func() { c.expireReservation(reservationIndex) }
+			expireTime = c.reservations[reservationIndex].EndTime
+		}
+
+		if pausedClient, ok := c.nextExpiredPause(t); ok && (expire == nil || c.pauseEndTime(pausedClient).Before(expireTime)) {
+			expire = func() { c.expirePause(pausedClient) }
+			expireTime = c.pauseEndTime(pausedClient)
+		}
+
+		if waitingClient, ok := c.nextExpiredWait(t); ok && (expire == nil || c.waitEndTime(waitingClient).Before(expireTime)) {
+			expire = func() { c.expireWait(waitingClient) }
+		}
+
+		if expire == nil {
+			return
+		}
+
+		expire()
+	}
+}
+
+// leaveTable frees the table of the seated or paused client, the pause is not
+// billed, and gives the table to the next waiting client.
+func (c *computerClubServiceImpl) leaveTable(client *Client, eventTime time.Time) {
+	busyTableId := client.BusyTableId
+
+	if client.State == StateClientIsPaused {
+		c.releaseSeat(client)
+	} else {
+		c.freeTable(busyTableId, eventTime)
+	}
+
+	c.seatClientFromQueue(busyTableId, eventTime)
+}
+
+// seatClientFromQueue gives the table, that has just been freed, to the next
+// waiting client. The table reserved at the moment is given only to the client,
+// who holds it.
+func (c *computerClubServiceImpl) seatClientFromQueue(tableId TableId, eventTime time.Time) {
+	if c.clientQueue.IsEmpty() {
+		return
+	}
+
+	var clientFromQueue *Client
+
+	if reservation, ok := c.activeReservation(tableId, eventTime); ok {
+		if !c.clientQueue.Remove(reservation.ClientName) {
+			return
+		}
+
+		client := c.clients[reservation.ClientName]
+		clientFromQueue = &client
+	} else {
+		clientFromQueue = c.clientQueue.Pop(eventTime)
+	}
+
+	c.claimReservation(tableId, clientFromQueue.Name)
+	c.takeTable(tableId, eventTime, clientFromQueue)
+
+	c.outcomes.addOutgoingEvent(eventTime, OutgoingEventClientTookPlace, clientFromQueue.Name, tableId)
+
+	c.fillQueueFromWaitingArea(eventTime)
+}
+
 func (c *computerClubServiceImpl) freeTable(tableId TableId, endTime time.Time) {
 	table := c.tables[tableId]
 
 	table.EndTime = endTime
 	table.State = StateTableIsFree
-	table.calculateProfit(c.pricePerHour)
+	table.calculateProfit(c.tariffSchedules[table.Category], c.billingPolicy)
 	table.calculateUsageTime()
+	table.PlayedParts = nil
 
 	c.tables[tableId] = table
 }
 
+// isBusyTable reports whether the table can not be taken, a table out of
+// service or kept for a paused client is busy, but not billed.
 func (c *computerClubServiceImpl) isBusyTable(tableId TableId) bool {
 	table := c.tables[tableId]
-	return table.State == StateTableIsBusy
+	return table.State != StateTableIsFree
 }
 
 func (c *computerClubServiceImpl) addClient(clientName ClientName) {
@@ -266,17 +721,33 @@ func (c *computerClubServiceImpl) addClient(clientName ClientName) {
 	c.clients[clientName] = client
 }
 
-func (c *computerClubServiceImpl) addClientToQueue(clientName ClientName) {
+func (c *computerClubServiceImpl) addClientToQueue(clientName ClientName, eventTime time.Time) {
 	client := c.clients[clientName]
 	client.State = StateClientIsWaiting
+	// a client from the waiting area has been waiting since coming there
+	if client.WaitingSince.IsZero() {
+		client.WaitingSince = eventTime
+	}
 	c.clients[clientName] = client
-	c.clientQueue.Push(&client)
+	c.clientQueue.Push(&client, c.memberships[clientName])
+	c.updateQueuePeak(eventTime)
+}
+
+func (c *computerClubServiceImpl) newClientQueue() *ClientQueue {
+	return NewClientQueue(c.queueSize, c.queuePolicy)
 }
 
 func (c *computerClubServiceImpl) deleteClient(clientName ClientName) {
 	delete(c.clients, clientName)
 }
 
+// isClientWaiting reports whether the client waits in the queue or in the
+// waiting area.
+func (c *computerClubServiceImpl) isClientWaiting(clientName ClientName) bool {
+	client, ok := c.clients[clientName]
+	return ok && (client.State == StateClientIsWaiting || client.State == StateClientIsInWaitingArea)
+}
+
 func (c *computerClubServiceImpl) isClientInComputerClub(clientName ClientName) bool {
 	_, ok := c.clients[clientName]
 	return ok
@@ -286,9 +757,11 @@ func (c *computerClubServiceImpl) isNonWorkingHours(time time.Time) bool {
 	return time.Before(c.openingTime) || time.After(c.closingTime)
 }
 
-func (c *computerClubServiceImpl) isThereFreeTable() bool {
+// isThereFreeTable reports whether the client can take a table at the given
+// time, the tables reserved for the client are free for the client.
+func (c *computerClubServiceImpl) isThereFreeTable(eventTime time.Time, clientName ClientName) bool {
 	for _, table := range c.tables {
-		if table.State == StateTableIsFree {
+		if table.State == StateTableIsFree && !c.isReservedFor(table.Id, eventTime, clientName) {
 			return true
 		}
 	}
@@ -1,9 +1,12 @@
 package computerclub
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
 	"slices"
+	"sync"
 	"testing"
 	"time"
 )
@@ -422,6 +425,46 @@ func TestProcessEventClientLeft(t *testing.T) {
 	}
 }
 
+func TestClientLeftWithoutTable(t *testing.T) {
+	config, err := getConfig(2)
+	if err != nil {
+		t.Fatalf("TestClientLeftWithoutTable: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestClientLeftWithoutTable: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("09:20"), "client1")
+	if err != nil {
+		t.Fatalf("TestClientLeftWithoutTable: %s", err.Error())
+	}
+
+	if occupancy := computerClubService.GetOccupancy(); len(occupancy) != config.TablesCount {
+		err = fmt.Errorf("expected occupancy of %d tables, got: '%+v'", config.TablesCount, occupancy)
+		t.Fatalf("TestClientLeftWithoutTable: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:20 4 client1\n" +
+		"19:00\n" +
+		"1 0 00:00\n" +
+		"2 0 00:00\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestClientLeftWithoutTable: %s", err.Error())
+	}
+}
+
 func TestProcessEventClientLeftError(t *testing.T) {
 	config, err := getConfig(1)
 	if err != nil {
@@ -440,6 +483,2093 @@ func TestProcessEventClientLeftError(t *testing.T) {
 	}
 }
 
+func TestCloseWithBillingPolicy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		billingPolicy  BillingPolicy
+		usageTime      time.Duration
+		expectedProfit int
+	}{
+		{
+			name:           "hourly",
+			billingPolicy:  NewHourlyBillingPolicy(),
+			usageTime:      time.Hour + time.Minute,
+			expectedProfit: 20,
+		},
+		{
+			name:           "per_minute",
+			billingPolicy:  NewPerMinuteBillingPolicy(),
+			usageTime:      time.Hour + 30*time.Minute,
+			expectedProfit: 15,
+		},
+		{
+			name:           "per_minute_rounds_up",
+			billingPolicy:  NewPerMinuteBillingPolicy(),
+			usageTime:      time.Minute,
+			expectedProfit: 1,
+		},
+		{
+			name:           "block_15_minutes",
+			billingPolicy:  NewBlockBillingPolicy(15 * time.Minute),
+			usageTime:      time.Hour + 16*time.Minute,
+			expectedProfit: 15,
+		},
+		{
+			name:           "grace_5_minutes_inside",
+			billingPolicy:  NewGraceBillingPolicy(5 * time.Minute),
+			usageTime:      2*time.Hour + 5*time.Minute,
+			expectedProfit: 20,
+		},
+		{
+			name:           "grace_5_minutes_outside",
+			billingPolicy:  NewGraceBillingPolicy(5 * time.Minute),
+			usageTime:      2*time.Hour + 6*time.Minute,
+			expectedProfit: 30,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config, err := getConfig(1)
+			if err != nil {
+				t.Fatalf("TestCloseWithBillingPolicy: %s", err.Error())
+			}
+
+			config.BillingPolicy = testCase.billingPolicy
+
+			computerClubService := NewComputerClub(config)
+
+			eventTime := config.OpeningTime.Add(time.Minute)
+			clientName := ClientName("client1")
+			tableId := TableId(1)
+
+			err = computerClubService.ProcessEventClientArrived(eventTime, clientName)
+			if err != nil {
+				t.Fatalf("TestCloseWithBillingPolicy: %s", err.Error())
+			}
+
+			err = computerClubService.ProcessEventClientTookPlace(eventTime, clientName, tableId)
+			if err != nil {
+				t.Fatalf("TestCloseWithBillingPolicy: %s", err.Error())
+			}
+
+			err = computerClubService.ProcessEventClientLeft(eventTime.Add(testCase.usageTime), clientName)
+			if err != nil {
+				t.Fatalf("TestCloseWithBillingPolicy: %s", err.Error())
+			}
+
+			computerClubService.Close()
+
+			var workingDayReport WorkingDayReport
+
+			table := Table{UsageTimePerDay: testCase.usageTime}
+			workingDayReport.writeTableReport(tableId, testCase.expectedProfit, table.usageTimePerDayString())
+
+			expectedWorkingDayReport := computerClubService.GetWorkingDayReport()
+
+			if !slices.Equal(expectedWorkingDayReport[len(expectedWorkingDayReport)-len(workingDayReport):], workingDayReport) {
+				err = fmt.Errorf("invalid wokring day report: expected: '%v', got: '%v'", string(expectedWorkingDayReport), string(workingDayReport))
+				t.Fatalf("TestCloseWithBillingPolicy: %v", err)
+			}
+		})
+	}
+}
+
+func TestCloseWithTariffs(t *testing.T) {
+	const layout = "15:04"
+
+	eveningStartTime, _ := time.Parse(layout, "18:00")
+	eveningEndTime, _ := time.Parse(layout, "19:00")
+	nightStartTime, _ := time.Parse(layout, "18:30")
+	nightEndTime, _ := time.Parse(layout, "09:30")
+
+	testCases := []struct {
+		name           string
+		tariffs        []Tariff
+		billingPolicy  BillingPolicy
+		startTime      string
+		endTime        string
+		expectedProfit int
+	}{
+		{
+			name:           "single_tariff_segment",
+			tariffs:        []Tariff{{StartTime: eveningStartTime, EndTime: eveningEndTime, PricePerHour: 20}},
+			billingPolicy:  NewPerMinuteBillingPolicy(),
+			startTime:      "18:00",
+			endTime:        "18:30",
+			expectedProfit: 10,
+		},
+		{
+			name:           "split_at_tariff_start",
+			tariffs:        []Tariff{{StartTime: eveningStartTime, EndTime: eveningEndTime, PricePerHour: 20}},
+			billingPolicy:  NewPerMinuteBillingPolicy(),
+			startTime:      "17:30",
+			endTime:        "19:00",
+			expectedProfit: 25,
+		},
+		{
+			name:           "split_at_tariff_end_past_midnight",
+			tariffs:        []Tariff{{StartTime: nightStartTime, EndTime: nightEndTime, PricePerHour: 20}},
+			billingPolicy:  NewPerMinuteBillingPolicy(),
+			startTime:      "09:00",
+			endTime:        "10:00",
+			expectedProfit: 15,
+		},
+		{
+			name:           "rounded_time_billed_at_used_tariffs",
+			tariffs:        []Tariff{{StartTime: eveningStartTime, EndTime: eveningEndTime, PricePerHour: 20}},
+			billingPolicy:  NewHourlyBillingPolicy(),
+			startTime:      "17:30",
+			endTime:        "18:10",
+			expectedProfit: 13,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config, err := getConfig(1)
+			if err != nil {
+				t.Fatalf("TestCloseWithTariffs: %s", err.Error())
+			}
+
+			config.Tariffs = testCase.tariffs
+			config.BillingPolicy = testCase.billingPolicy
+
+			computerClubService := NewComputerClub(config)
+
+			startTime, _ := time.Parse(layout, testCase.startTime)
+			endTime, _ := time.Parse(layout, testCase.endTime)
+			clientName := ClientName("client1")
+			tableId := TableId(1)
+
+			err = computerClubService.ProcessEventClientArrived(startTime, clientName)
+			if err != nil {
+				t.Fatalf("TestCloseWithTariffs: %s", err.Error())
+			}
+
+			err = computerClubService.ProcessEventClientTookPlace(startTime, clientName, tableId)
+			if err != nil {
+				t.Fatalf("TestCloseWithTariffs: %s", err.Error())
+			}
+
+			err = computerClubService.ProcessEventClientLeft(endTime, clientName)
+			if err != nil {
+				t.Fatalf("TestCloseWithTariffs: %s", err.Error())
+			}
+
+			computerClubService.Close()
+
+			var workingDayReport WorkingDayReport
+
+			table := Table{UsageTimePerDay: endTime.Sub(startTime)}
+			workingDayReport.writeTableReport(tableId, testCase.expectedProfit, table.usageTimePerDayString())
+
+			expectedWorkingDayReport := computerClubService.GetWorkingDayReport()
+
+			if !slices.Equal(expectedWorkingDayReport[len(expectedWorkingDayReport)-len(workingDayReport):], workingDayReport) {
+				err = fmt.Errorf("invalid wokring day report: expected: '%v', got: '%v'", string(expectedWorkingDayReport), string(workingDayReport))
+				t.Fatalf("TestCloseWithTariffs: %v", err)
+			}
+		})
+	}
+}
+
+func TestCloseWithTableCategories(t *testing.T) {
+	config, err := getConfig(3)
+	if err != nil {
+		t.Fatalf("TestCloseWithTableCategories: %s", err.Error())
+	}
+
+	config.Categories = []TableCategory{
+		{Name: "vip", PricePerHour: 30, TableIds: []TableId{2, 3}},
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	eventTime := config.OpeningTime.Add(time.Minute)
+	clientName1 := ClientName("client1")
+	clientName2 := ClientName("client2")
+
+	err = computerClubService.ProcessEventClientArrived(eventTime, clientName1)
+	if err != nil {
+		t.Fatalf("TestCloseWithTableCategories: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(eventTime, clientName1, 1)
+	if err != nil {
+		t.Fatalf("TestCloseWithTableCategories: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(eventTime, clientName2)
+	if err != nil {
+		t.Fatalf("TestCloseWithTableCategories: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(eventTime, clientName2, 3)
+	if err != nil {
+		t.Fatalf("TestCloseWithTableCategories: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	var workingDayReport WorkingDayReport
+
+	workingDayReport.writeTime(config.ClosingTime)
+	workingDayReport.writeTableReportWithCategory(1, 100, "09:59", StandardTableCategory)
+	workingDayReport.writeTableReportWithCategory(2, 0, "00:00", "vip")
+	workingDayReport.writeTableReportWithCategory(3, 300, "09:59", "vip")
+	workingDayReport.writeCategoryReport(StandardTableCategory, 100)
+	workingDayReport.writeCategoryReport("vip", 300)
+
+	expectedWorkingDayReport := computerClubService.GetWorkingDayReport()
+
+	if !slices.Equal(expectedWorkingDayReport[len(expectedWorkingDayReport)-len(workingDayReport):], workingDayReport) {
+		err = fmt.Errorf("invalid wokring day report: expected: '%v', got: '%v'", string(expectedWorkingDayReport), string(workingDayReport))
+		t.Fatalf("TestCloseWithTableCategories: %v", err)
+	}
+}
+
+func TestOvernight(t *testing.T) {
+	const layout = "15:04"
+
+	openingTime, _ := time.Parse(layout, "18:00")
+	closingTime, _ := time.Parse(layout, "04:00")
+
+	config := &Config{
+		TablesCount:  1,
+		OpeningTime:  openingTime,
+		ClosingTime:  closingTime,
+		PricePerHour: 10,
+	}
+
+	if !config.IsOvernight() {
+		t.Fatalf("TestOvernight: expected overnight config")
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	clientName1 := ClientName("client1")
+	clientName2 := ClientName("client2")
+	tableId := TableId(1)
+
+	arrivalTime := openingTime.Add(5 * time.Hour)
+	afterMidnightTime := closingTime.Add(24*time.Hour - time.Hour)
+	afterClosingTime := closingTime.Add(24*time.Hour + time.Hour)
+
+	err := computerClubService.ProcessEventClientArrived(arrivalTime, clientName1)
+	if err != nil {
+		t.Fatalf("TestOvernight: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(arrivalTime, clientName1, tableId)
+	if err != nil {
+		t.Fatalf("TestOvernight: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(afterMidnightTime, clientName2)
+	if err != nil {
+		t.Fatalf("TestOvernight: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(afterMidnightTime, clientName1)
+	if err != nil {
+		t.Fatalf("TestOvernight: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(afterClosingTime, ClientName("client3"))
+	if !errors.Is(err, ErrNotOpenYet) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrNotOpenYet, err)
+		t.Fatalf("TestOvernight: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	var workingDayReport WorkingDayReport
+
+	workingDayReport.writeTime(closingTime)
+	workingDayReport.writeTableReport(tableId, 40, "04:00")
+
+	expectedWorkingDayReport := computerClubService.GetWorkingDayReport()
+
+	if !slices.Equal(expectedWorkingDayReport[len(expectedWorkingDayReport)-len(workingDayReport):], workingDayReport) {
+		err = fmt.Errorf("invalid wokring day report: expected: '%v', got: '%v'", string(expectedWorkingDayReport), string(workingDayReport))
+		t.Fatalf("TestOvernight: %v", err)
+	}
+}
+
+func TestGetPeriodReport(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestGetPeriodReport: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	clientName := ClientName("client1")
+	tableId := TableId(1)
+
+	firstDate := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	secondDate := firstDate.AddDate(0, 0, 1)
+
+	for _, date := range []time.Time{firstDate, secondDate} {
+		computerClubService.OpenOn(date)
+
+		eventTime := date.Add(10 * time.Hour)
+
+		err = computerClubService.ProcessEventClientArrived(eventTime, clientName)
+		if err != nil {
+			t.Fatalf("TestGetPeriodReport: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientTookPlace(eventTime, clientName, tableId)
+		if err != nil {
+			t.Fatalf("TestGetPeriodReport: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientLeft(eventTime.Add(90*time.Minute), clientName)
+		if err != nil {
+			t.Fatalf("TestGetPeriodReport: %s", err.Error())
+		}
+
+		computerClubService.Close()
+
+		var workingDayReport WorkingDayReport
+
+		workingDayReport.writeTime(date.Add(9 * time.Hour))
+		workingDayReport.writeEvent(eventTime, IncomingEventClientArrived, clientName)
+		workingDayReport.writeEventWithTableId(eventTime, IncomingEventClientTookPlace, clientName, tableId)
+		workingDayReport.writeEvent(eventTime.Add(90*time.Minute), IncomingEventClientLeft, clientName)
+		workingDayReport.writeTime(date.Add(19 * time.Hour))
+		workingDayReport.writeTableReport(tableId, 20, "01:30")
+
+		expectedWorkingDayReport := computerClubService.GetWorkingDayReport()
+
+		if !slices.Equal(workingDayReport, expectedWorkingDayReport) {
+			err = fmt.Errorf("invalid wokring day report: expected: '%v', got: '%v'", string(expectedWorkingDayReport), string(workingDayReport))
+			t.Fatalf("TestGetPeriodReport: %v", err)
+		}
+	}
+
+	var periodReport WorkingDayReport
+
+	periodReport.writePeriod(firstDate, secondDate)
+	periodReport.writeTableReport(tableId, 40, "03:00")
+
+	expectedPeriodReport := computerClubService.GetPeriodReport()
+
+	if !slices.Equal(periodReport, expectedPeriodReport) {
+		err = fmt.Errorf("invalid period report: expected: '%v', got: '%v'", string(expectedPeriodReport), string(periodReport))
+		t.Fatalf("TestGetPeriodReport: %v", err)
+	}
+}
+
+func TestGetOutcomes(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestGetOutcomes: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	eventTime := config.OpeningTime.Add(time.Minute)
+	clientName1 := ClientName("client1")
+	clientName2 := ClientName("client2")
+	tableId := TableId(1)
+
+	err = computerClubService.ProcessEventClientArrived(eventTime, clientName1)
+	if err != nil {
+		t.Fatalf("TestGetOutcomes: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(eventTime, clientName1, tableId)
+	if err != nil {
+		t.Fatalf("TestGetOutcomes: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(eventTime, clientName2, tableId)
+	if !errors.Is(err, ErrPlaceIsBusy) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrPlaceIsBusy, err)
+		t.Fatalf("TestGetOutcomes: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	outcomes := []Outcome{
+		{Kind: OutcomeKindOpening, Time: config.OpeningTime},
+		{Kind: OutcomeKindIncomingEvent, Time: eventTime, EventType: IncomingEventClientArrived, ClientName: clientName1},
+		{Kind: OutcomeKindIncomingEvent, Time: eventTime, EventType: IncomingEventClientTookPlace, ClientName: clientName1, TableId: tableId},
+		{Kind: OutcomeKindIncomingEvent, Time: eventTime, EventType: IncomingEventClientTookPlace, ClientName: clientName2, TableId: tableId},
+		{Kind: OutcomeKindOutgoingEvent, Time: eventTime, EventType: OutgoingEventError, Err: ErrPlaceIsBusy},
+		{Kind: OutcomeKindOutgoingEvent, Time: config.ClosingTime, EventType: OutgoingEventClientLeft, ClientName: clientName1},
+		{Kind: OutcomeKindClosing, Time: config.ClosingTime},
+		{Kind: OutcomeKindTableSummary, TableId: tableId, Profit: 100, UsageTime: config.ClosingTime.Sub(eventTime)},
+	}
+
+	expectedOutcomes := computerClubService.GetOutcomes()
+
+	if !slices.Equal(outcomes, expectedOutcomes) {
+		err = fmt.Errorf("invalid outcomes: expected: '%v', got: '%v'", expectedOutcomes, outcomes)
+		t.Fatalf("TestGetOutcomes: %v", err)
+	}
+
+	workingDayReport := NewWorkingDayReport(outcomes)
+
+	expectedWorkingDayReport := computerClubService.GetWorkingDayReport()
+
+	if !slices.Equal(workingDayReport, expectedWorkingDayReport) {
+		err = fmt.Errorf("invalid wokring day report: expected: '%v', got: '%v'", string(expectedWorkingDayReport), string(workingDayReport))
+		t.Fatalf("TestGetOutcomes: %v", err)
+	}
+}
+
+func TestQueryMethods(t *testing.T) {
+	config, err := getConfig(2)
+	if err != nil {
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	eventTime := config.OpeningTime.Add(time.Hour)
+	clientName1 := ClientName("client1")
+	clientName2 := ClientName("client2")
+	clientName3 := ClientName("client3")
+	clientName4 := ClientName("client4")
+
+	for _, clientName := range []ClientName{clientName1, clientName2, clientName3, clientName4} {
+		err = computerClubService.ProcessEventClientArrived(eventTime, clientName)
+		if err != nil {
+			t.Fatalf("TestQueryMethods: %s", err.Error())
+		}
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(eventTime, clientName1, 2)
+	if err != nil {
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(eventTime.Add(time.Hour), clientName2, 1)
+	if err != nil {
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(eventTime.Add(time.Hour), clientName4)
+	if err != nil {
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+
+	occupancy := []TableOccupancy{
+		{TableId: 1, Category: StandardTableCategory, IsBusy: true, ClientName: clientName2, BusySince: eventTime.Add(time.Hour)},
+		{TableId: 2, Category: StandardTableCategory, IsBusy: true, ClientName: clientName1, BusySince: eventTime},
+	}
+
+	expectedOccupancy := computerClubService.GetOccupancy()
+
+	if !slices.Equal(occupancy, expectedOccupancy) {
+		err = fmt.Errorf("invalid occupancy: expected: '%v', got: '%v'", expectedOccupancy, occupancy)
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+
+	clients := []Client{
+		{Name: clientName1, State: StateClientTookPlace, BusyTableId: 2},
+		{Name: clientName2, State: StateClientTookPlace, BusyTableId: 1},
+		{Name: clientName3, State: StateClientArrived},
+		{Name: clientName4, State: StateClientIsWaiting, WaitingSince: eventTime.Add(time.Hour)},
+	}
+
+	expectedClients := computerClubService.GetClients()
+
+	if !slices.Equal(clients, expectedClients) {
+		err = fmt.Errorf("invalid clients: expected: '%v', got: '%v'", expectedClients, clients)
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+
+	queue := []QueuedClient{
+		{Position: 1, ClientName: clientName4},
+	}
+
+	expectedQueue := computerClubService.GetQueue(eventTime)
+
+	if !slices.Equal(queue, expectedQueue) {
+		err = fmt.Errorf("invalid queue: expected: '%v', got: '%v'", expectedQueue, queue)
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+
+	// the first table is busy for 31 minutes, the second one for 1 hour 31 minutes
+	revenue := 10 + 20
+
+	expectedRevenue := computerClubService.GetRunningRevenue(eventTime.Add(time.Hour + 31*time.Minute))
+
+	if revenue != expectedRevenue {
+		err = fmt.Errorf("invalid running revenue: expected: '%d', got: '%d'", expectedRevenue, revenue)
+		t.Fatalf("TestQueryMethods: %s", err.Error())
+	}
+}
+
+func TestConcurrentEvents(t *testing.T) {
+	const clientsCount = 100
+
+	config, err := getConfig(clientsCount)
+	if err != nil {
+		t.Fatalf("TestConcurrentEvents: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	eventTime := config.OpeningTime.Add(time.Hour)
+
+	var wg sync.WaitGroup
+
+	for i := 1; i <= clientsCount; i++ {
+		wg.Add(1)
+
+		go func(tableId TableId) {
+			defer wg.Done()
+
+			clientName := ClientName(fmt.Sprintf("client%d", tableId))
+
+			computerClubService.ProcessEventClientArrived(eventTime, clientName)
+
+			// every client tries the first table, but only one of them gets it
+			computerClubService.ProcessEventClientTookPlace(eventTime, clientName, 1)
+			computerClubService.ProcessEventClientTookPlace(eventTime, clientName, tableId)
+
+			computerClubService.GetOccupancy()
+			computerClubService.GetClients()
+			computerClubService.GetQueue(eventTime)
+			computerClubService.GetRunningRevenue(eventTime)
+			computerClubService.GetWorkingDayReport()
+		}(TableId(i))
+	}
+
+	wg.Wait()
+
+	computerClubService.Close()
+
+	outcomes := computerClubService.GetOutcomes()
+
+	var errorsCount int
+
+	for i, outcome := range outcomes {
+		if outcome.Err == nil {
+			continue
+		}
+
+		// the error of an event follows the event at once
+		previousOutcome := outcomes[i-1]
+		if previousOutcome.Kind != OutcomeKindIncomingEvent || previousOutcome.EventType != IncomingEventClientTookPlace ||
+			!errors.Is(outcome.Err, ErrPlaceIsBusy) {
+			err = fmt.Errorf("expected error: '%v' after the event taking place, got: '%v' after: '%v'", ErrPlaceIsBusy, outcome, previousOutcome)
+			t.Fatalf("TestConcurrentEvents: %s", err.Error())
+		}
+
+		errorsCount++
+	}
+
+	// the opening, every client arrives and takes a table twice, leaves at the
+	// closing, the closing and the summary of every table
+	expectedOutcomesCount := 1 + clientsCount*3 + errorsCount + clientsCount + 1 + clientsCount
+	if len(outcomes) != expectedOutcomesCount {
+		err = fmt.Errorf("expected %d outcomes, got: %d", expectedOutcomesCount, len(outcomes))
+		t.Fatalf("TestConcurrentEvents: %s", err.Error())
+	}
+}
+
+func TestEqualTimeEventsOrder(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestEqualTimeEventsOrder: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	eventTime := config.OpeningTime.Add(time.Hour)
+	clientName1 := ClientName("client1")
+	clientName2 := ClientName("client2")
+	tableId := TableId(1)
+
+	var wg sync.WaitGroup
+
+	// the second client arrives strictly after the first one took the table
+	firstTookPlace := make(chan struct{})
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		computerClubService.ProcessEventClientArrived(eventTime, clientName1)
+		computerClubService.ProcessEventClientTookPlace(eventTime, clientName1, tableId)
+		close(firstTookPlace)
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		<-firstTookPlace
+		computerClubService.ProcessEventClientArrived(eventTime, clientName2)
+		computerClubService.ProcessEventClientTookPlace(eventTime, clientName2, tableId)
+	}()
+
+	wg.Wait()
+
+	workingDayReport := computerClubService.GetWorkingDayReport()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"10:00 1 client1\n" +
+		"10:00 2 client1 1\n" +
+		"10:00 1 client2\n" +
+		"10:00 2 client2 1\n" +
+		"10:00 13 PlaceIsBusy\n"
+
+	if string(workingDayReport) != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, string(workingDayReport))
+		t.Fatalf("TestEqualTimeEventsOrder: %s", err.Error())
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	config, err := getConfig(2)
+	if err != nil {
+		t.Fatalf("TestSnapshotRestore: %s", err.Error())
+	}
+
+	eventTime := config.OpeningTime.Add(time.Hour)
+	clientName1 := ClientName("client1")
+	clientName2 := ClientName("client2")
+	clientName3 := ClientName("client3")
+	clientName4 := ClientName("client4")
+
+	processFirstEvents := func(computerClubService ComputerClubService) {
+		computerClubService.Open()
+		computerClubService.ProcessEventClientArrived(eventTime.Add(-2*time.Hour), clientName1)
+		computerClubService.ProcessEventClientArrived(eventTime, clientName1)
+		computerClubService.ProcessEventClientTookPlace(eventTime, clientName1, 1)
+		computerClubService.ProcessEventClientArrived(eventTime, clientName2)
+		computerClubService.ProcessEventClientTookPlace(eventTime, clientName2, 2)
+		computerClubService.ProcessEventClientArrived(eventTime, clientName3)
+		computerClubService.ProcessEventClientWaiting(eventTime, clientName3)
+		computerClubService.ProcessEventClientArrived(eventTime, clientName4)
+		computerClubService.ProcessEventClientWaiting(eventTime, clientName4)
+	}
+
+	processLastEvents := func(computerClubService ComputerClubService) {
+		computerClubService.ProcessEventClientLeft(eventTime.Add(time.Hour), clientName1)
+		computerClubService.ProcessEventClientLeft(eventTime.Add(2*time.Hour), clientName2)
+		computerClubService.Close()
+	}
+
+	uninterruptedService := NewComputerClub(config)
+	processFirstEvents(uninterruptedService)
+	processLastEvents(uninterruptedService)
+
+	stoppedService := NewComputerClub(config)
+	processFirstEvents(stoppedService)
+
+	data, err := json.Marshal(stoppedService.Snapshot())
+	if err != nil {
+		t.Fatalf("TestSnapshotRestore: %s", err.Error())
+	}
+
+	var snapshot Snapshot
+
+	err = json.Unmarshal(data, &snapshot)
+	if err != nil {
+		t.Fatalf("TestSnapshotRestore: %s", err.Error())
+	}
+
+	restoredService := NewComputerClub(config)
+
+	err = restoredService.Restore(snapshot)
+	if err != nil {
+		t.Fatalf("TestSnapshotRestore: %s", err.Error())
+	}
+
+	processLastEvents(restoredService)
+
+	workingDayReport := uninterruptedService.GetWorkingDayReport()
+	expectedWorkingDayReport := restoredService.GetWorkingDayReport()
+
+	if !slices.Equal(workingDayReport, expectedWorkingDayReport) {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", string(expectedWorkingDayReport), string(workingDayReport))
+		t.Fatalf("TestSnapshotRestore: %s", err.Error())
+	}
+
+	otherConfig, err := getConfig(3)
+	if err != nil {
+		t.Fatalf("TestSnapshotRestore: %s", err.Error())
+	}
+
+	err = NewComputerClub(otherConfig).Restore(snapshot)
+	if !errors.Is(err, ErrInvalidSnapshot) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrInvalidSnapshot, err)
+		t.Fatalf("TestSnapshotRestore: %s", err.Error())
+	}
+}
+
+func TestReservations(t *testing.T) {
+	config, err := getConfig(2)
+	if err != nil {
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientReserved(at("09:05"), "client1", 1, at("12:00"), at("13:00"))
+	if err != nil {
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientReserved(at("09:10"), "client2", 1, at("12:30"), at("14:00"))
+	if !errors.Is(err, ErrPlaceIsBusy) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrPlaceIsBusy, err)
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientReserved(at("09:20"), "client3", 2, at("10:00"), at("11:00"))
+	if err != nil {
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("12:10"), "client4")
+	if err != nil {
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("12:15"), "client4", 1)
+	if !errors.Is(err, ErrPlaceIsBusy) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrPlaceIsBusy, err)
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("12:20"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("12:20"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("12:30"), "client4", 2)
+	if err != nil {
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:05 5 client1 1 12:00 13:00\n" +
+		"09:10 5 client2 1 12:30 14:00\n" +
+		"09:10 13 PlaceIsBusy\n" +
+		"09:20 5 client3 2 10:00 11:00\n" +
+		"11:00 14 client3 2\n" +
+		"12:10 1 client4\n" +
+		"12:15 2 client4 1\n" +
+		"12:15 13 PlaceIsBusy\n" +
+		"12:20 1 client1\n" +
+		"12:20 2 client1 1\n" +
+		"12:30 2 client4 2\n" +
+		"19:00 11 client1\n" +
+		"19:00 11 client4\n" +
+		"19:00\n" +
+		"1 70 06:40\n" +
+		"2 70 06:30\n" +
+		"2 no-show client3 10:00 11:00\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestReservations: %s", err.Error())
+	}
+}
+
+func TestReservationInvalidTime(t *testing.T) {
+	tests := []struct {
+		name        string
+		strTime     string
+		strStart    string
+		strEnd      string
+		expectedErr error
+	}{
+		{"before opening", "08:00", "10:00", "11:00", ErrNotOpenYet},
+		{"window in the past", "10:30", "10:00", "11:00", ErrInvalidReservationTime},
+		{"empty window", "09:10", "11:00", "11:00", ErrInvalidReservationTime},
+		{"window past closing", "09:10", "18:30", "19:30", ErrInvalidReservationTime},
+	}
+
+	for _, test := range tests {
+		config, err := getConfig(1)
+		if err != nil {
+			t.Fatalf("TestReservationInvalidTime: %s", err.Error())
+		}
+
+		computerClubService := NewComputerClub(config)
+
+		computerClubService.Open()
+
+		err = computerClubService.ProcessEventClientReserved(at(test.strTime), "client1", 1, at(test.strStart), at(test.strEnd))
+		if !errors.Is(err, test.expectedErr) {
+			err = fmt.Errorf("%s: expected error: '%v', got: '%v'", test.name, test.expectedErr, err)
+			t.Fatalf("TestReservationInvalidTime: %s", err.Error())
+		}
+	}
+}
+
+func TestReservationHoldsTableFromQueue(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestReservationHoldsTableFromQueue: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientReserved(at("09:00"), "client2", 1, at("10:00"), at("11:00"))
+	if err != nil {
+		t.Fatalf("TestReservationHoldsTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("10:05"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservationHoldsTableFromQueue: %s", err.Error())
+	}
+
+	// the only free table is reserved, so the client can wait
+	err = computerClubService.ProcessEventClientWaiting(at("10:05"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservationHoldsTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("11:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservationHoldsTableFromQueue: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:00 5 client2 1 10:00 11:00\n" +
+		"10:05 1 client1\n" +
+		"10:05 3 client1\n" +
+		"11:00 14 client2 1\n" +
+		"11:00 12 client1 1\n" +
+		"11:10 4 client1\n" +
+		"19:00\n" +
+		"1 10 00:10\n" +
+		"1 no-show client2 10:00 11:00\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestReservationHoldsTableFromQueue: %s", err.Error())
+	}
+}
+
+func TestReservationHolderFindsTableTaken(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientReserved(at("09:00"), "client2", 1, at("12:00"), at("13:00"))
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("11:50"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("11:50"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("12:05"), "client2")
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	// the client, who holds the table, finds it taken and is not a no-show
+	err = computerClubService.ProcessEventClientTookPlace(at("12:05"), "client2", 1)
+	if !errors.Is(err, ErrPlaceIsBusy) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrPlaceIsBusy, err)
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("12:05"), "client2")
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("12:30"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("13:30"), "client2")
+	if err != nil {
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:00 5 client2 1 12:00 13:00\n" +
+		"11:50 1 client1\n" +
+		"11:50 2 client1 1\n" +
+		"12:05 1 client2\n" +
+		"12:05 2 client2 1\n" +
+		"12:05 13 PlaceIsBusy\n" +
+		"12:05 3 client2\n" +
+		"12:30 4 client1\n" +
+		"12:30 12 client2 1\n" +
+		"13:30 4 client2\n" +
+		"19:00\n" +
+		"1 20 01:40\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestReservationHolderFindsTableTaken: %s", err.Error())
+	}
+}
+
+func TestReservationHolderTakesTableFromQueue(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientReserved(at("09:00"), "client3", 1, at("10:00"), at("11:00"))
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("10:10"), "client3")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("10:10"), "client3")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	// the reserved table goes to the client, who holds it, not to the first one in the queue
+	err = computerClubService.ProcessEventClientLeft(at("10:20"), "client1")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("10:50"), "client3")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("11:00"), "client2")
+	if err != nil {
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:00 5 client3 1 10:00 11:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 3 client2\n" +
+		"10:10 1 client3\n" +
+		"10:10 3 client3\n" +
+		"10:20 4 client1\n" +
+		"10:20 12 client3 1\n" +
+		"10:50 4 client3\n" +
+		"10:50 12 client2 1\n" +
+		"11:00 4 client2\n" +
+		"19:00\n" +
+		"1 40 01:50\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestReservationHolderTakesTableFromQueue: %s", err.Error())
+	}
+}
+
+func TestTableOutOfService(t *testing.T) {
+	config, err := getConfig(2)
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:20"), "client2", 2)
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:30"), "client3")
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:30"), "client3")
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	// there is no free table, so the client goes to the head of the queue
+	err = computerClubService.ProcessEventTableOutOfService(at("10:00"), "admin", 1)
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("10:30"), "client2")
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("11:00"), "client3", 1)
+	if !errors.Is(err, ErrPlaceIsBusy) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrPlaceIsBusy, err)
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventTableOutOfService(at("11:30"), "admin", 1)
+	if !errors.Is(err, ErrTableIsOutOfService) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrTableIsOutOfService, err)
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventTableBackInService(at("12:00"), "admin", 1)
+	if err != nil {
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventTableBackInService(at("12:30"), "admin", 2)
+	if !errors.Is(err, ErrTableIsInService) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrTableIsInService, err)
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 2 client2 2\n" +
+		"09:30 1 client3\n" +
+		"09:30 3 client3\n" +
+		"10:00 6 admin 1\n" +
+		"10:00 15 client1\n" +
+		"10:30 4 client2\n" +
+		"10:30 12 client1 2\n" +
+		"11:00 2 client3 1\n" +
+		"11:00 13 PlaceIsBusy\n" +
+		"11:30 6 admin 1\n" +
+		"11:30 13 TableIsOutOfService\n" +
+		"12:00 7 admin 1\n" +
+		"12:00 12 client3 1\n" +
+		"12:30 7 admin 2\n" +
+		"12:30 13 TableIsInService\n" +
+		"19:00 11 client1\n" +
+		"19:00 11 client3\n" +
+		"19:00\n" +
+		"1 80 07:50\n" +
+		"1 downtime 02:00\n" +
+		"2 110 09:40\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestTableOutOfService: %s", err.Error())
+	}
+}
+
+func TestTableOutOfServiceMovesClient(t *testing.T) {
+	config, err := getConfig(2)
+	if err != nil {
+		t.Fatalf("TestTableOutOfServiceMovesClient: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestTableOutOfServiceMovesClient: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestTableOutOfServiceMovesClient: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventTableOutOfService(at("10:00"), "admin", 1)
+	if err != nil {
+		t.Fatalf("TestTableOutOfServiceMovesClient: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"10:00 6 admin 1\n" +
+		"10:00 12 client1 2\n" +
+		"19:00 11 client1\n" +
+		"19:00\n" +
+		"1 10 00:50\n" +
+		"1 downtime 09:00\n" +
+		"2 90 09:00\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestTableOutOfServiceMovesClient: %s", err.Error())
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientPaused(at("10:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("10:10"), "client2")
+	if err != nil {
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("10:20"), "client2", 1)
+	if !errors.Is(err, ErrPlaceIsBusy) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrPlaceIsBusy, err)
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientPaused(at("10:30"), "client2")
+	if !errors.Is(err, ErrClientIsNotSeated) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrClientIsNotSeated, err)
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientResumed(at("11:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientResumed(at("11:30"), "client1")
+	if !errors.Is(err, ErrClientIsNotPaused) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrClientIsNotPaused, err)
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("12:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"10:00 8 client1\n" +
+		"10:10 1 client2\n" +
+		"10:20 2 client2 1\n" +
+		"10:20 13 PlaceIsBusy\n" +
+		"10:30 8 client2\n" +
+		"10:30 13 ClientIsNotSeated\n" +
+		"11:00 9 client1\n" +
+		"11:30 9 client1\n" +
+		"11:30 13 ClientIsNotPaused\n" +
+		"12:00 4 client1\n" +
+		"19:00 11 client2\n" +
+		"19:00\n" +
+		"1 20 01:50\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestPauseAndResume: %s", err.Error())
+	}
+}
+
+func TestPausedSessionBilledOnce(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("10:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("10:00"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientPaused(at("10:30"), "client1")
+	if err != nil {
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+
+	if revenue := computerClubService.GetRunningRevenue(at("10:35")); revenue != 10 {
+		err = fmt.Errorf("expected running revenue: 10, got: %d", revenue)
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientResumed(at("10:40"), "client1")
+	if err != nil {
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("11:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	// an hour of play is billed once, though it is split by the pause
+	expectedWorkingDayReport := "09:00\n" +
+		"10:00 1 client1\n" +
+		"10:00 2 client1 1\n" +
+		"10:30 8 client1\n" +
+		"10:40 9 client1\n" +
+		"11:10 4 client1\n" +
+		"19:00\n" +
+		"1 10 01:00\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestPausedSessionBilledOnce: %s", err.Error())
+	}
+}
+
+func TestPauseExpires(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+
+	config.MaxPauseDuration = 30 * time.Minute
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientPaused(at("10:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientResumed(at("10:40"), "client1")
+	if !errors.Is(err, ErrClientIsNotPaused) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrClientIsNotPaused, err)
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 3 client2\n" +
+		"10:00 8 client1\n" +
+		"10:30 16 client1 1\n" +
+		"10:30 12 client2 1\n" +
+		"10:40 9 client1\n" +
+		"10:40 13 ClientIsNotPaused\n" +
+		"19:00 11 client1\n" +
+		"19:00 11 client2\n" +
+		"19:00\n" +
+		"1 100 09:20\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestPauseExpires: %s", err.Error())
+	}
+}
+
+func TestPauseNearClosing(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestPauseNearClosing: %s", err.Error())
+	}
+
+	config.MaxPauseDuration = 30 * time.Minute
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("18:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseNearClosing: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("18:00"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestPauseNearClosing: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientPaused(at("18:50"), "client1")
+	if err != nil {
+		t.Fatalf("TestPauseNearClosing: %s", err.Error())
+	}
+
+	// the pause, that would run out after closing, ends with the working day
+	err = computerClubService.ProcessEventClientArrived(at("19:30"), "client2")
+	if !errors.Is(err, ErrNotOpenYet) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrNotOpenYet, err)
+		t.Fatalf("TestPauseNearClosing: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"18:00 1 client1\n" +
+		"18:00 2 client1 1\n" +
+		"18:50 8 client1\n" +
+		"19:30 1 client2\n" +
+		"19:30 13 NotOpenYet\n" +
+		"19:00 11 client1\n" +
+		"19:00\n" +
+		"1 10 00:50\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestPauseNearClosing: %s", err.Error())
+	}
+}
+
+func TestWaitingClientLeft(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:30"), "client3")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:30"), "client3")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("10:00"), "client2")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	expectedQueue := []QueuedClient{{Position: 1, ClientName: "client3"}}
+	if queue := computerClubService.GetQueue(at("10:00")); !slices.Equal(queue, expectedQueue) {
+		err = fmt.Errorf("invalid queue: expected: %v, got: %v", expectedQueue, queue)
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("11:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("12:00"), "client3")
+	if err != nil {
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 3 client2\n" +
+		"09:30 1 client3\n" +
+		"09:30 3 client3\n" +
+		"10:00 4 client2\n" +
+		"11:00 4 client1\n" +
+		"11:00 12 client3 1\n" +
+		"12:00 4 client3\n" +
+		"19:00\n" +
+		"1 30 02:50\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestWaitingClientLeft: %s", err.Error())
+	}
+}
+
+func TestClientQueueRemove(t *testing.T) {
+	clientQueue := NewClientQueue(3, NewFIFOQueuePolicy())
+
+	clientQueue.Push(&Client{Name: "client1"}, 0)
+	clientQueue.Push(&Client{Name: "client2"}, 0)
+	clientQueue.Push(&Client{Name: "client3"}, 0)
+
+	if !clientQueue.Remove("client2") {
+		t.Fatalf("TestClientQueueRemove: %s", "expected client2 to be removed")
+	}
+
+	if clientQueue.Remove("client4") {
+		t.Fatalf("TestClientQueueRemove: %s", "expected unknown client not to be removed")
+	}
+
+	expectedClientNames := []ClientName{"client1", "client3"}
+	if clientNames := clientQueue.ClientNames(); !slices.Equal(clientNames, expectedClientNames) {
+		err := fmt.Errorf("invalid client names: expected: %v, got: %v", expectedClientNames, clientNames)
+		t.Fatalf("TestClientQueueRemove: %s", err.Error())
+	}
+
+	if clientQueue.IsFull() {
+		t.Fatalf("TestClientQueueRemove: %s", "expected queue not to be full")
+	}
+}
+
+func TestQueuePolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		queuePolicy   QueuePolicy
+		leftTime      string
+		expectedQueue []ClientName
+		expectedEvent string
+	}{
+		{"fifo", nil, "10:00", []ClientName{"client2", "client3"}, "10:00 12 client2 1\n"},
+		{"priority", NewPriorityQueuePolicy(), "10:20", []ClientName{"client3", "client2"}, "10:20 12 client3 1\n"},
+		{"aging before step", NewAgingQueuePolicy(30 * time.Minute), "10:00", []ClientName{"client3", "client2"}, "10:00 12 client3 1\n"},
+		{"aging after step", NewAgingQueuePolicy(30 * time.Minute), "10:20", []ClientName{"client2", "client3"}, "10:20 12 client2 1\n"},
+	}
+
+	for _, test := range tests {
+		config, err := getConfig(1)
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		config.QueuePolicy = test.queuePolicy
+		config.Memberships = map[ClientName]int{"client3": 1}
+
+		computerClubService := NewComputerClub(config)
+
+		computerClubService.Open()
+
+		err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientWaiting(at("09:20"), "client2")
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientArrived(at("09:30"), "client3")
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientWaiting(at("09:30"), "client3")
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		var queue []ClientName
+		for _, queuedClient := range computerClubService.GetQueue(at(test.leftTime)) {
+			queue = append(queue, queuedClient.ClientName)
+		}
+
+		if !slices.Equal(queue, test.expectedQueue) {
+			err = fmt.Errorf("%s: invalid queue: expected: %v, got: %v", test.name, test.expectedQueue, queue)
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientLeft(at(test.leftTime), "client1")
+		if err != nil {
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+
+		expectedWorkingDayReport := "09:00\n" +
+			"09:10 1 client1\n" +
+			"09:10 2 client1 1\n" +
+			"09:20 1 client2\n" +
+			"09:20 3 client2\n" +
+			"09:30 1 client3\n" +
+			"09:30 3 client3\n" +
+			test.leftTime + " 4 client1\n" +
+			test.expectedEvent
+
+		if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+			err = fmt.Errorf("%s: invalid working day report: expected: '%s', got: '%s'", test.name, expectedWorkingDayReport, workingDayReport)
+			t.Fatalf("TestQueuePolicies: %s", err.Error())
+		}
+	}
+}
+
+func TestQueueOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name             string
+		overflowPolicy   OverflowPolicy
+		expectedOverflow string
+		expectedSeating  string
+		expectedStats    QueueStats
+	}{
+		{"turn away", OverflowPolicyTurnAway, "09:30 11 client3\n", "10:00 12 client2 1\n", QueueStats{TurnedAway: 1}},
+		{"waiting area", OverflowPolicyWaitingArea, "09:30 17 client3\n", "10:00 12 client2 1\n10:00 15 client3\n", QueueStats{SentToWaitingArea: 1}},
+		{"partner club", OverflowPolicyPartnerClub, "09:30 18 client3\n", "10:00 12 client2 1\n", QueueStats{TurnedAway: 1}},
+	}
+
+	for _, test := range tests {
+		config, err := getConfig(1)
+		if err != nil {
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		config.QueueSize = 1
+		config.OverflowPolicy = test.overflowPolicy
+
+		computerClubService := NewComputerClub(config)
+
+		computerClubService.Open()
+
+		err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+		if err != nil {
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+		if err != nil {
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+		if err != nil {
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientWaiting(at("09:20"), "client2")
+		if err != nil {
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientArrived(at("09:30"), "client3")
+		if err != nil {
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientWaiting(at("09:30"), "client3")
+		if !errors.Is(err, ErrQueueIsFull) {
+			err = fmt.Errorf("%s: expected error: '%v', got: '%v'", test.name, ErrQueueIsFull, err)
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientLeft(at("10:00"), "client1")
+		if err != nil {
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		expectedWorkingDayReport := "09:00\n" +
+			"09:10 1 client1\n" +
+			"09:10 2 client1 1\n" +
+			"09:20 1 client2\n" +
+			"09:20 3 client2\n" +
+			"09:30 1 client3\n" +
+			"09:30 3 client3\n" +
+			test.expectedOverflow +
+			"10:00 4 client1\n" +
+			test.expectedSeating
+
+		if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+			err = fmt.Errorf("%s: invalid working day report: expected: '%s', got: '%s'", test.name, expectedWorkingDayReport, workingDayReport)
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+
+		queueStats := computerClubService.Snapshot().QueueStats
+		if queueStats.TurnedAway != test.expectedStats.TurnedAway || queueStats.SentToWaitingArea != test.expectedStats.SentToWaitingArea {
+			err = fmt.Errorf("%s: invalid queue stats: expected: '%+v', got: '%+v'", test.name, test.expectedStats, queueStats)
+			t.Fatalf("TestQueueOverflowPolicies: %s", err.Error())
+		}
+	}
+}
+
+func TestClientWaitingTwice(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	config.QueueSize = 1
+	config.OverflowPolicy = OverflowPolicyWaitingArea
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:30"), "client3")
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:30"), "client3")
+	if !errors.Is(err, ErrQueueIsFull) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrQueueIsFull, err)
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	// the clients, who already wait in the waiting area and in the queue, can not wait again
+	err = computerClubService.ProcessEventClientWaiting(at("09:40"), "client3")
+	if !errors.Is(err, ErrClientIsAlreadyWaiting) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrClientIsAlreadyWaiting, err)
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:45"), "client2")
+	if !errors.Is(err, ErrClientIsAlreadyWaiting) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrClientIsAlreadyWaiting, err)
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("10:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("11:00"), "client2")
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("12:00"), "client3")
+	if err != nil {
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 3 client2\n" +
+		"09:30 1 client3\n" +
+		"09:30 3 client3\n" +
+		"09:30 17 client3\n" +
+		"09:40 3 client3\n" +
+		"09:40 13 ClientIsAlreadyWaiting\n" +
+		"09:45 3 client2\n" +
+		"09:45 13 ClientIsAlreadyWaiting\n" +
+		"10:00 4 client1\n" +
+		"10:00 12 client2 1\n" +
+		"10:00 15 client3\n" +
+		"11:00 4 client2\n" +
+		"11:00 12 client3 1\n" +
+		"12:00 4 client3\n" +
+		"19:00\n" +
+		"1 30 02:50\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestClientWaitingTwice: %s", err.Error())
+	}
+}
+
+func TestMaxWaitDuration(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	config.MaxWaitDuration = 30 * time.Minute
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:10"), "client1")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:20"), "client2")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("09:40"), "client3")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("09:40"), "client3")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("10:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("11:00"), "client3")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:10 1 client1\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 3 client2\n" +
+		"09:40 1 client3\n" +
+		"09:40 3 client3\n" +
+		"09:50 11 client2\n" +
+		"10:00 4 client1\n" +
+		"10:00 12 client3 1\n" +
+		"11:00 4 client3\n" +
+		"19:00\n" +
+		"1 20 01:50\n" +
+		"wait 2 00:25 00:30 1\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	// the wait, that would run out after closing, ends with the working day
+	computerClubService = NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("18:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("18:00"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("18:50"), "client2")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientWaiting(at("18:50"), "client2")
+	if err != nil {
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientArrived(at("19:30"), "client3")
+	if !errors.Is(err, ErrNotOpenYet) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrNotOpenYet, err)
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport = "09:00\n" +
+		"18:00 1 client1\n" +
+		"18:00 2 client1 1\n" +
+		"18:50 1 client2\n" +
+		"18:50 3 client2\n" +
+		"19:30 1 client3\n" +
+		"19:30 13 NotOpenYet\n" +
+		"19:00 11 client1\n" +
+		"19:00 11 client2\n" +
+		"19:00\n" +
+		"1 10 01:00\n" +
+		"wait 1 00:10 00:10 0\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestMaxWaitDuration: %s", err.Error())
+	}
+}
+
+func TestQueueAnalytics(t *testing.T) {
+	config, err := getConfig(1)
+	if err != nil {
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+
+	config.QueueAnalytics = true
+
+	computerClubService := NewComputerClub(config)
+
+	computerClubService.Open()
+
+	err = computerClubService.ProcessEventClientArrived(at("09:05"), "client1")
+	if err != nil {
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+
+	// the client can not wait, as there is a free table
+	err = computerClubService.ProcessEventClientWaiting(at("09:05"), "client1")
+	if !errors.Is(err, ErrICanWaitNoLonger) {
+		err = fmt.Errorf("expected error: '%v', got: '%v'", ErrICanWaitNoLonger, err)
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientTookPlace(at("09:10"), "client1", 1)
+	if err != nil {
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+
+	// the last client does not fit in the queue
+	waits := []struct {
+		strTime     string
+		expectedErr error
+	}{
+		{"09:20", nil},
+		{"09:30", nil},
+		{"09:40", ErrQueueIsFull},
+	}
+
+	for i, wait := range waits {
+		clientName := ClientName(fmt.Sprintf("client%d", i+2))
+		err = computerClubService.ProcessEventClientArrived(at(wait.strTime), clientName)
+		if err != nil {
+			t.Fatalf("TestQueueAnalytics: %s", err.Error())
+		}
+
+		err = computerClubService.ProcessEventClientWaiting(at(wait.strTime), clientName)
+		if !errors.Is(err, wait.expectedErr) {
+			err = fmt.Errorf("expected error: '%v', got: '%v'", wait.expectedErr, err)
+			t.Fatalf("TestQueueAnalytics: %s", err.Error())
+		}
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("10:00"), "client1")
+	if err != nil {
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("10:30"), "client3")
+	if err != nil {
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+
+	err = computerClubService.ProcessEventClientLeft(at("11:00"), "client2")
+	if err != nil {
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+
+	computerClubService.Close()
+
+	expectedWorkingDayReport := "09:00\n" +
+		"09:05 1 client1\n" +
+		"09:05 3 client1\n" +
+		"09:05 13 ICanWaitNoLonger!\n" +
+		"09:10 2 client1 1\n" +
+		"09:20 1 client2\n" +
+		"09:20 3 client2\n" +
+		"09:30 1 client3\n" +
+		"09:30 3 client3\n" +
+		"09:40 1 client4\n" +
+		"09:40 3 client4\n" +
+		"09:40 11 client4\n" +
+		"10:00 4 client1\n" +
+		"10:00 12 client2 1\n" +
+		"10:30 4 client3\n" +
+		"11:00 4 client2\n" +
+		"19:00\n" +
+		"1 20 01:50\n" +
+		"analytics\n" +
+		"waited 2\n" +
+		"average-wait 00:50\n" +
+		"max-wait 01:00\n" +
+		"gave-up 0\n" +
+		"turned-away 1\n" +
+		"waiting-area 0\n" +
+		"rejected 1\n" +
+		"peak-queue 2 09:30\n"
+
+	if workingDayReport := string(computerClubService.GetWorkingDayReport()); workingDayReport != expectedWorkingDayReport {
+		err = fmt.Errorf("invalid working day report: expected: '%s', got: '%s'", expectedWorkingDayReport, workingDayReport)
+		t.Fatalf("TestQueueAnalytics: %s", err.Error())
+	}
+}
+
+// at parses the HH:MM time of a test event.
+func at(strTime string) time.Time {
+	eventTime, err := xtime.ParseHoursMinutesFromString(strTime)
+	if err != nil {
+		panic(err)
+	}
+	return eventTime
+}
+
 func getConfig(tablesCount int) (*Config, error) {
 	const layout = "15:04"
 
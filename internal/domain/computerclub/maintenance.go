@@ -0,0 +1,116 @@
+package computerclub
+
+import "time"
+
+// StaffName is the name of the staff member, who takes the tables out of
+// service and brings them back.
+type StaffName string
+
+// ProcessEventTableOutOfService takes the table out of service. The table is
+// busy, but not billed, until it is back in service. The client at the table
+// pays for the time so far and moves to a free table, or to the head of the
+// queue, when there is none. The table, that is already out of service, can not
+// be taken out of service again.
+func (c *computerClubServiceImpl) ProcessEventTableOutOfService(eventTime time.Time, staffName StaffName, tableId TableId) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventTableOutOfService, ClientName(staffName), tableId)
+
+	table := c.tables[tableId]
+	if table.State == StateTableIsOutOfService {
+		c.outcomes.addEventError(eventTime, ErrTableIsOutOfService)
+
+		return ErrTableIsOutOfService
+	}
+
+	var seatedClient *Client
+	switch table.State {
+	case StateTableIsBusy:
+		seatedClient = c.getSeatedClient(tableId)
+		c.freeTable(tableId, eventTime)
+	case StateTableIsPaused:
+		// the paused client loses the seat
+		pausedClient := c.getSeatedClient(tableId)
+		c.outcomes.addOutgoingEvent(eventTime, OutgoingEventPauseExpired, pausedClient.Name, tableId)
+		c.releaseSeat(pausedClient)
+	}
+
+	table = c.tables[tableId]
+	table.State = StateTableIsOutOfService
+	table.OutOfServiceSince = eventTime
+	c.tables[tableId] = table
+
+	if seatedClient != nil {
+		c.moveClient(seatedClient, eventTime)
+	}
+
+	return nil
+}
+
+// ProcessEventTableBackInService brings the table back in service and gives it
+// to the next waiting client. Only the table, that is out of service, can be
+// brought back.
+func (c *computerClubServiceImpl) ProcessEventTableBackInService(eventTime time.Time, staffName StaffName, tableId TableId) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventTableBackInService, ClientName(staffName), tableId)
+
+	table := c.tables[tableId]
+	if table.State != StateTableIsOutOfService {
+		c.outcomes.addEventError(eventTime, ErrTableIsInService)
+
+		return ErrTableIsInService
+	}
+
+	c.bringBackInService(tableId, eventTime)
+	c.seatClientFromQueue(tableId, eventTime)
+
+	return nil
+}
+
+func (c *computerClubServiceImpl) bringBackInService(tableId TableId, eventTime time.Time) {
+	table := c.tables[tableId]
+	table.State = StateTableIsFree
+	table.DowntimePerDay += eventTime.Sub(table.OutOfServiceSince)
+	c.tables[tableId] = table
+}
+
+// moveClient seats the client, whose table went out of service, at the free
+// table with the lowest id or puts the client at the head of the queue.
+func (c *computerClubServiceImpl) moveClient(client *Client, eventTime time.Time) {
+	for tableId := TableId(minTablesCount); tableId <= TableId(c.tablesCount); tableId++ {
+		if c.isBusyTable(tableId) || c.isReservedFor(tableId, eventTime, client.Name) {
+			continue
+		}
+
+		c.claimReservation(tableId, client.Name)
+		c.takeTable(tableId, eventTime, client)
+		c.outcomes.addOutgoingEvent(eventTime, OutgoingEventClientTookPlace, client.Name, tableId)
+
+		return
+	}
+
+	client.State = StateClientIsWaiting
+	client.BusyTableId = 0
+	client.WaitingSince = eventTime
+	c.clients[client.Name] = *client
+	c.clientQueue.PushFront(client, c.memberships[client.Name])
+	c.updateQueuePeak(eventTime)
+
+	c.outcomes.addOutgoingEvent(eventTime, OutgoingEventClientWaiting, client.Name, 0)
+}
+
+func (c *computerClubServiceImpl) getSeatedClient(tableId TableId) *Client {
+	for _, client := range c.clients {
+		if (client.State == StateClientTookPlace || client.State == StateClientIsPaused) && client.BusyTableId == tableId {
+			return &client
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package computerclub
+
+import "time"
+
+const (
+	OutcomeKindOpening uint8 = iota
+	OutcomeKindIncomingEvent
+	OutcomeKindOutgoingEvent
+	OutcomeKindClosing
+	OutcomeKindTableSummary
+	OutcomeKindCategorySummary
+	OutcomeKindPeriod
+	OutcomeKindNoShow
+	OutcomeKindWaitSummary
+	OutcomeKindQueueAnalytics
+)
+
+// Outcome is a single record of what happened in the computer club. Only the
+// fields relevant to its kind are set.
+type Outcome struct {
+	Kind uint8
+
+	// Time of the opening, the closing or the event
+	Time time.Time
+
+	// EventType, ClientName and TableId describe an incoming or outgoing event.
+	// TableId is zero for events without table, Err is set for the error event.
+	EventType  uint8
+	ClientName ClientName
+	TableId    TableId
+	Err        error
+
+	// ReservationStart and ReservationEnd bound the window of a reservation
+	// event and of a no-show
+	ReservationStart time.Time
+	ReservationEnd   time.Time
+
+	// Category is empty in a table summary, if the club has no table categories
+	Category  string
+	Profit    int
+	UsageTime time.Duration
+	Downtime  time.Duration
+
+	StartDate time.Time
+	EndDate   time.Time
+
+	// WaitStats are set in the wait summary and in the queue analytics,
+	// QueueStats in the queue analytics only
+	WaitStats  WaitStats
+	QueueStats QueueStats
+}
+
+type Outcomes []Outcome
+
+func (o *Outcomes) addOpening(openingTime time.Time) {
+	*o = append(*o, Outcome{
+		Kind: OutcomeKindOpening,
+		Time: openingTime,
+	})
+}
+
+func (o *Outcomes) addClosing(closingTime time.Time) {
+	*o = append(*o, Outcome{
+		Kind: OutcomeKindClosing,
+		Time: closingTime,
+	})
+}
+
+func (o *Outcomes) addIncomingEvent(eventTime time.Time, eventType uint8, clientName ClientName, tableId TableId) {
+	*o = append(*o, Outcome{
+		Kind:       OutcomeKindIncomingEvent,
+		Time:       eventTime,
+		EventType:  eventType,
+		ClientName: clientName,
+		TableId:    tableId,
+	})
+}
+
+func (o *Outcomes) addOutgoingEvent(eventTime time.Time, eventType uint8, clientName ClientName, tableId TableId) {
+	*o = append(*o, Outcome{
+		Kind:       OutcomeKindOutgoingEvent,
+		Time:       eventTime,
+		EventType:  eventType,
+		ClientName: clientName,
+		TableId:    tableId,
+	})
+}
+
+func (o *Outcomes) addReservationEvent(eventTime time.Time, reservation Reservation) {
+	*o = append(*o, Outcome{
+		Kind:             OutcomeKindIncomingEvent,
+		Time:             eventTime,
+		EventType:        IncomingEventClientReserved,
+		ClientName:       reservation.ClientName,
+		TableId:          reservation.TableId,
+		ReservationStart: reservation.StartTime,
+		ReservationEnd:   reservation.EndTime,
+	})
+}
+
+func (o *Outcomes) addEventError(eventTime time.Time, err error) {
+	*o = append(*o, Outcome{
+		Kind:      OutcomeKindOutgoingEvent,
+		Time:      eventTime,
+		EventType: OutgoingEventError,
+		Err:       err,
+	})
+}
+
+func (o *Outcomes) addWaitSummary(waitStats WaitStats) {
+	*o = append(*o, Outcome{
+		Kind:      OutcomeKindWaitSummary,
+		WaitStats: waitStats,
+	})
+}
+
+func (o *Outcomes) addQueueAnalytics(waitStats WaitStats, queueStats QueueStats) {
+	*o = append(*o, Outcome{
+		Kind:       OutcomeKindQueueAnalytics,
+		WaitStats:  waitStats,
+		QueueStats: queueStats,
+	})
+}
+
+func (o *Outcomes) addPeriod(startDate time.Time, endDate time.Time) {
+	*o = append(*o, Outcome{
+		Kind:      OutcomeKindPeriod,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+}
+
+// addTablesSummary adds the summary of every table followed by its no-shows.
+// The downtime of a table, that has been out of service, is a part of its
+// summary.
+// Once there are table categories besides the standard one, the summary of
+// each category follows.
+func (o *Outcomes) addTablesSummary(tablesCount int, tables map[TableId]Table, categories []string, noShows []Reservation) {
+	hasCategories := len(categories) > 1
+
+	categoryProfits := make(map[string]int)
+
+	for tableId := TableId(minTablesCount); tableId <= TableId(tablesCount); tableId++ {
+		table := tables[tableId]
+
+		outcome := Outcome{
+			Kind:      OutcomeKindTableSummary,
+			TableId:   tableId,
+			Profit:    table.Profit,
+			UsageTime: table.UsageTimePerDay,
+			Downtime:  table.DowntimePerDay,
+		}
+		if hasCategories {
+			outcome.Category = table.Category
+		}
+
+		*o = append(*o, outcome)
+
+		for _, noShow := range noShows {
+			if noShow.TableId != tableId {
+				continue
+			}
+
+			*o = append(*o, Outcome{
+				Kind:             OutcomeKindNoShow,
+				TableId:          tableId,
+				ClientName:       noShow.ClientName,
+				ReservationStart: noShow.StartTime,
+				ReservationEnd:   noShow.EndTime,
+			})
+		}
+
+		categoryProfits[table.Category] += table.Profit
+	}
+
+	if !hasCategories {
+		return
+	}
+
+	for _, category := range categories {
+		*o = append(*o, Outcome{
+			Kind:     OutcomeKindCategorySummary,
+			Category: category,
+			Profit:   categoryProfits[category],
+		})
+	}
+}
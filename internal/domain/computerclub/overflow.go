@@ -0,0 +1,68 @@
+package computerclub
+
+import (
+	"slices"
+	"time"
+)
+
+// OverflowPolicy decides what happens to the client, who can not wait, as the
+// queue is full.
+type OverflowPolicy uint8
+
+const (
+	// OverflowPolicyTurnAway makes the client leave the club
+	OverflowPolicyTurnAway OverflowPolicy = iota
+	// OverflowPolicyWaitingArea sends the client to the waiting area, from
+	// which the clients join the queue, once it has room
+	OverflowPolicyWaitingArea
+	// OverflowPolicyPartnerClub refers the client to the partner club
+	OverflowPolicyPartnerClub
+)
+
+// overflowQueue handles the client, who can not wait in the full queue, as the
+// overflow policy says.
+func (c *computerClubServiceImpl) overflowQueue(clientName ClientName, eventTime time.Time) {
+	switch c.overflowPolicy {
+	case OverflowPolicyWaitingArea:
+		c.queueStats.SentToWaitingArea++
+
+		client := c.clients[clientName]
+		client.State = StateClientIsInWaitingArea
+		client.WaitingSince = eventTime
+		c.clients[clientName] = client
+		c.waitingArea = append(c.waitingArea, clientName)
+
+		c.outcomes.addOutgoingEvent(eventTime, OutgoingEventClientInWaitingArea, clientName, 0)
+	case OverflowPolicyPartnerClub:
+		c.queueStats.TurnedAway++
+
+		c.deleteClient(clientName)
+
+		c.outcomes.addOutgoingEvent(eventTime, OutgoingEventClientReferred, clientName, 0)
+	default:
+		c.queueStats.TurnedAway++
+
+		c.deleteClient(clientName)
+
+		c.outcomes.addOutgoingEvent(eventTime, OutgoingEventClientLeft, clientName, 0)
+	}
+}
+
+// fillQueueFromWaitingArea moves the clients from the waiting area to the end
+// of the queue in the order they came, while the queue has room.
+func (c *computerClubServiceImpl) fillQueueFromWaitingArea(eventTime time.Time) {
+	for len(c.waitingArea) > 0 && !c.clientQueue.IsFull() {
+		clientName := c.waitingArea[0]
+		c.waitingArea = c.waitingArea[1:]
+
+		c.addClientToQueue(clientName, eventTime)
+
+		c.outcomes.addOutgoingEvent(eventTime, OutgoingEventClientWaiting, clientName, 0)
+	}
+}
+
+func (c *computerClubServiceImpl) removeFromWaitingArea(clientName ClientName) {
+	c.waitingArea = slices.DeleteFunc(c.waitingArea, func(waitingClientName ClientName) bool {
+		return waitingClientName == clientName
+	})
+}
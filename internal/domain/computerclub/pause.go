@@ -0,0 +1,129 @@
+package computerclub
+
+import "time"
+
+// ProcessEventClientPaused pauses the session of the seated client. The table
+// is kept for the client, but is not billed during the pause. The session is
+// billed as a whole, once the client leaves the table. The seat is released,
+// once the pause is longer than the max pause duration of the club.
+func (c *computerClubServiceImpl) ProcessEventClientPaused(eventTime time.Time, clientName ClientName) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventClientPaused, clientName, 0)
+
+	if !c.isClientInComputerClub(clientName) {
+		c.outcomes.addEventError(eventTime, ErrClientUnknown)
+
+		return ErrClientUnknown
+	}
+
+	client := c.clients[clientName]
+
+	if client.State != StateClientTookPlace {
+		c.outcomes.addEventError(eventTime, ErrClientIsNotSeated)
+
+		return ErrClientIsNotSeated
+	}
+
+	table := c.tables[client.BusyTableId]
+	table.PlayedParts = append(table.PlayedParts, SessionPart{StartTime: table.StartTime, EndTime: eventTime})
+	table.State = StateTableIsPaused
+	c.tables[client.BusyTableId] = table
+
+	client.State = StateClientIsPaused
+	client.PausedSince = eventTime
+	c.clients[clientName] = client
+
+	return nil
+}
+
+// ProcessEventClientResumed resumes the session of the paused client at the
+// same table.
+func (c *computerClubServiceImpl) ProcessEventClientResumed(eventTime time.Time, clientName ClientName) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	c.outcomes.addIncomingEvent(eventTime, IncomingEventClientResumed, clientName, 0)
+
+	if !c.isClientInComputerClub(clientName) {
+		c.outcomes.addEventError(eventTime, ErrClientUnknown)
+
+		return ErrClientUnknown
+	}
+
+	client := c.clients[clientName]
+
+	if client.State != StateClientIsPaused {
+		c.outcomes.addEventError(eventTime, ErrClientIsNotPaused)
+
+		return ErrClientIsNotPaused
+	}
+
+	client.PausedSince = time.Time{}
+	c.takeTable(client.BusyTableId, eventTime, &client)
+
+	return nil
+}
+
+// nextExpiredPause returns the paused client, whose pause runs out first by the
+// given time.
+func (c *computerClubServiceImpl) nextExpiredPause(t time.Time) (*Client, bool) {
+	if c.maxPauseDuration == 0 {
+		return nil, false
+	}
+
+	var next *Client
+
+	for _, client := range c.clients {
+		if client.State != StateClientIsPaused || c.pauseEndTime(&client).After(t) {
+			continue
+		}
+
+		if next == nil || client.PausedSince.Before(next.PausedSince) ||
+			client.PausedSince.Equal(next.PausedSince) && client.Name < next.Name {
+			next = &client
+		}
+	}
+
+	return next, next != nil
+}
+
+func (c *computerClubServiceImpl) pauseEndTime(client *Client) time.Time {
+	return client.PausedSince.Add(c.maxPauseDuration)
+}
+
+// expirePause releases the seat of the client, whose pause has run out, to the
+// next waiting client. The client stays in the club without a table.
+func (c *computerClubServiceImpl) expirePause(client *Client) {
+	pauseEndTime := c.pauseEndTime(client)
+	tableId := client.BusyTableId
+
+	c.outcomes.addOutgoingEvent(pauseEndTime, OutgoingEventPauseExpired, client.Name, tableId)
+
+	c.releaseSeat(client)
+	c.seatClientFromQueue(tableId, pauseEndTime)
+}
+
+// releaseSeat frees the table of the paused client, the session is billed
+// without the pause.
+func (c *computerClubServiceImpl) releaseSeat(client *Client) {
+	table := c.tables[client.BusyTableId]
+
+	// the session ended, once the client paused
+	lastPlayedPart := table.PlayedParts[len(table.PlayedParts)-1]
+	table.PlayedParts = table.PlayedParts[:len(table.PlayedParts)-1]
+	table.StartTime = lastPlayedPart.StartTime
+	c.tables[client.BusyTableId] = table
+
+	c.freeTable(client.BusyTableId, lastPlayedPart.EndTime)
+
+	client.State = StateClientArrived
+	client.BusyTableId = 0
+	client.PausedSince = time.Time{}
+	c.clients[client.Name] = *client
+}
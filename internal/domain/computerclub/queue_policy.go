@@ -0,0 +1,66 @@
+package computerclub
+
+import "time"
+
+// WaitingClient is a client in the queue as the queue policy sees it. A higher
+// membership level goes first, zero is no membership.
+type WaitingClient struct {
+	Name            ClientName
+	MembershipLevel int
+	WaitingSince    time.Time
+}
+
+// QueuePolicy decides, which waiting client takes the next free table.
+type QueuePolicy interface {
+	// Next returns the index of the client, that takes a table at the given
+	// time. The clients are in the order they started waiting.
+	Next(waitingClients []WaitingClient, t time.Time) int
+}
+
+type fifoQueuePolicy struct{}
+
+// NewFIFOQueuePolicy seats the clients in the order they started waiting.
+func NewFIFOQueuePolicy() QueuePolicy {
+	return &fifoQueuePolicy{}
+}
+
+func (p *fifoQueuePolicy) Next(waitingClients []WaitingClient, t time.Time) int {
+	return 0
+}
+
+type priorityQueuePolicy struct {
+	agingStep time.Duration
+}
+
+// NewPriorityQueuePolicy seats the clients with the highest membership level
+// first, the clients of the same level in the order they started waiting.
+func NewPriorityQueuePolicy() QueuePolicy {
+	return &priorityQueuePolicy{}
+}
+
+// NewAgingQueuePolicy seats the clients as the priority policy does, but every
+// aging step of waiting raises the level of the client by one, so that the
+// clients without membership are not left waiting forever.
+func NewAgingQueuePolicy(agingStep time.Duration) QueuePolicy {
+	return &priorityQueuePolicy{agingStep: agingStep}
+}
+
+func (p *priorityQueuePolicy) Next(waitingClients []WaitingClient, t time.Time) int {
+	next := 0
+
+	for i := 1; i < len(waitingClients); i++ {
+		if p.priority(&waitingClients[i], t) > p.priority(&waitingClients[next], t) {
+			next = i
+		}
+	}
+
+	return next
+}
+
+func (p *priorityQueuePolicy) priority(waitingClient *WaitingClient, t time.Time) int {
+	priority := waitingClient.MembershipLevel
+	if p.agingStep > 0 {
+		priority += int(t.Sub(waitingClient.WaitingSince) / p.agingStep)
+	}
+	return priority
+}
@@ -0,0 +1,130 @@
+package computerclub
+
+import (
+	"slices"
+	"time"
+)
+
+// Reservation holds the table for the client from StartTime until EndTime.
+type Reservation struct {
+	TableId    TableId
+	ClientName ClientName
+	StartTime  time.Time
+	EndTime    time.Time
+}
+
+func (r *Reservation) isActiveAt(t time.Time) bool {
+	return !t.Before(r.StartTime) && t.Before(r.EndTime)
+}
+
+func (r *Reservation) overlaps(other *Reservation) bool {
+	return r.TableId == other.TableId && r.StartTime.Before(other.EndTime) && other.StartTime.Before(r.EndTime)
+}
+
+// ProcessEventClientReserved holds the table for the client from startTime
+// until endTime. The client does not have to be in the club. Another client
+// taking the table inside the window gets PlaceIsBusy, the reservation expires
+// at endTime, unless the client takes the table before. The window, that starts
+// before the event, is empty or ends after closing, is invalid.
+func (c *computerClubServiceImpl) ProcessEventClientReserved(eventTime time.Time, clientName ClientName, tableId TableId, startTime time.Time, endTime time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(eventTime)
+
+	reservation := Reservation{
+		TableId:    tableId,
+		ClientName: clientName,
+		StartTime:  startTime,
+		EndTime:    endTime,
+	}
+
+	c.outcomes.addReservationEvent(eventTime, reservation)
+
+	if c.isNonWorkingHours(eventTime) {
+		c.outcomes.addEventError(eventTime, ErrNotOpenYet)
+
+		return ErrNotOpenYet
+	}
+
+	if startTime.Before(eventTime) || !endTime.After(startTime) || endTime.After(c.closingTime) {
+		c.outcomes.addEventError(eventTime, ErrInvalidReservationTime)
+
+		return ErrInvalidReservationTime
+	}
+
+	for i := range c.reservations {
+		if c.reservations[i].overlaps(&reservation) {
+			c.outcomes.addEventError(eventTime, ErrPlaceIsBusy)
+
+			return ErrPlaceIsBusy
+		}
+	}
+
+	c.reservations = append(c.reservations, reservation)
+
+	return nil
+}
+
+// nextExpiredReservation returns the index of the reservation, that ends first
+// by the given time.
+func (c *computerClubServiceImpl) nextExpiredReservation(t time.Time) (int, bool) {
+	next := -1
+
+	for i, reservation := range c.reservations {
+		if reservation.EndTime.After(t) {
+			continue
+		}
+		if next < 0 || reservation.EndTime.Before(c.reservations[next].EndTime) {
+			next = i
+		}
+	}
+
+	return next, next >= 0
+}
+
+// expireReservation removes the reservation, that was not taken, at its end. A
+// client from the queue takes the table, if it is free.
+func (c *computerClubServiceImpl) expireReservation(index int) {
+	reservation := c.reservations[index]
+	c.reservations = slices.Delete(c.reservations, index, index+1)
+
+	c.outcomes.addOutgoingEvent(reservation.EndTime, OutgoingEventReservationExpired, reservation.ClientName, reservation.TableId)
+
+	c.noShows = append(c.noShows, reservation)
+
+	if !c.isBusyTable(reservation.TableId) {
+		c.seatClientFromQueue(reservation.TableId, reservation.EndTime)
+	}
+}
+
+// isReservedFor reports whether the table is held at the given time for a
+// client other than the given one, an empty name stands for no client.
+func (c *computerClubServiceImpl) isReservedFor(tableId TableId, t time.Time, clientName ClientName) bool {
+	for i := range c.reservations {
+		reservation := &c.reservations[i]
+		if reservation.TableId == tableId && reservation.ClientName != clientName && reservation.isActiveAt(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeReservation returns the reservation, that holds the table at the given
+// time.
+func (c *computerClubServiceImpl) activeReservation(tableId TableId, t time.Time) (Reservation, bool) {
+	for _, reservation := range c.reservations {
+		if reservation.TableId == tableId && reservation.isActiveAt(t) {
+			return reservation, true
+		}
+	}
+	return Reservation{}, false
+}
+
+// claimReservation removes the reservation of the table by the client, who has
+// taken it or has found it busy.
+func (c *computerClubServiceImpl) claimReservation(tableId TableId, clientName ClientName) {
+	c.reservations = slices.DeleteFunc(c.reservations, func(reservation Reservation) bool {
+		return reservation.TableId == tableId && reservation.ClientName == clientName
+	})
+}
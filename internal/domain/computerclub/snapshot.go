@@ -0,0 +1,211 @@
+package computerclub
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// outcomeErrors are the errors, that outcomes of a restored snapshot may have
+var outcomeErrors = []error{
+	ErrNotOpenYet,
+	ErrYouShallNotPass,
+	ErrClientUnknown,
+	ErrPlaceIsBusy,
+	ErrICanWaitNoLonger,
+	ErrClientIsNotSeated,
+	ErrClientIsNotPaused,
+	ErrClientIsAlreadyWaiting,
+	ErrTableIsOutOfService,
+	ErrTableIsInService,
+	ErrInvalidReservationTime,
+}
+
+// Snapshot is the full state of the service. It holds no config, so it must be
+// restored by a service created with the same config.
+type Snapshot struct {
+	OpeningTime time.Time
+	ClosingTime time.Time
+
+	Tables  []Table
+	Clients []Client
+	// Queue lists the waiting clients in the order they are kept
+	Queue []ClientName
+	// QueueFrontSize is the number of clients at the head of the queue, that
+	// go first regardless of the queue policy
+	QueueFrontSize int `json:",omitempty"`
+	// WaitingArea lists the clients, that did not fit in the queue
+	WaitingArea []ClientName `json:",omitempty"`
+	WaitStats   WaitStats
+	QueueStats  QueueStats
+
+	Reservations []Reservation
+	NoShows      []Reservation
+
+	Outcomes []SnapshotOutcome
+
+	PeriodTables    []Table
+	PeriodStartDate time.Time
+	PeriodEndDate   time.Time
+}
+
+// SnapshotOutcome is an outcome with the error kept as its text.
+type SnapshotOutcome struct {
+	Outcome
+	Err string `json:",omitempty"`
+}
+
+// Snapshot returns the state of the service, that the same service restores.
+func (c *computerClubServiceImpl) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := Snapshot{
+		OpeningTime:     c.openingTime,
+		ClosingTime:     c.closingTime,
+		Tables:          sortedTables(c.tables),
+		Clients:         make([]Client, 0, len(c.clients)),
+		Queue:           c.clientQueue.ClientNames(),
+		QueueFrontSize:  c.clientQueue.FrontSize(),
+		WaitingArea:     slices.Clone(c.waitingArea),
+		WaitStats:       c.waitStats,
+		QueueStats:      c.queueStats,
+		Reservations:    slices.Clone(c.reservations),
+		NoShows:         slices.Clone(c.noShows),
+		Outcomes:        make([]SnapshotOutcome, 0, len(c.outcomes)),
+		PeriodTables:    sortedTables(c.periodTables),
+		PeriodStartDate: c.periodStartDate,
+		PeriodEndDate:   c.periodEndDate,
+	}
+
+	for _, client := range c.clients {
+		snapshot.Clients = append(snapshot.Clients, client)
+	}
+
+	slices.SortFunc(snapshot.Clients, func(a, b Client) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	})
+
+	for _, outcome := range c.outcomes {
+		snapshotOutcome := SnapshotOutcome{Outcome: outcome}
+		if outcome.Err != nil {
+			snapshotOutcome.Err = outcome.Err.Error()
+		}
+		snapshotOutcome.Outcome.Err = nil
+
+		snapshot.Outcomes = append(snapshot.Outcomes, snapshotOutcome)
+	}
+
+	return snapshot
+}
+
+// Restore replaces the state of the service with the snapshot, so that later
+// events continue the day, as if the service was never stopped.
+func (c *computerClubServiceImpl) Restore(snapshot Snapshot) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(snapshot.Tables) != c.tablesCount {
+		return ErrInvalidSnapshot
+	}
+
+	tables := make(map[TableId]Table, len(snapshot.Tables))
+	for _, table := range snapshot.Tables {
+		if table.Id < minTablesCount || int(table.Id) > c.tablesCount {
+			return ErrInvalidSnapshot
+		}
+		// a paused session has been played before the pause
+		if table.State == StateTableIsPaused && len(table.PlayedParts) == 0 {
+			return ErrInvalidSnapshot
+		}
+		table.PlayedParts = slices.Clone(table.PlayedParts)
+		tables[table.Id] = table
+	}
+
+	periodTables := make(map[TableId]Table, len(snapshot.PeriodTables))
+	for _, table := range snapshot.PeriodTables {
+		periodTables[table.Id] = table
+	}
+
+	clients := make(map[ClientName]Client, len(snapshot.Clients))
+	for _, client := range snapshot.Clients {
+		clients[client.Name] = client
+	}
+
+	if snapshot.QueueFrontSize < 0 || snapshot.QueueFrontSize > len(snapshot.Queue) {
+		return ErrInvalidSnapshot
+	}
+
+	clientQueue := c.newClientQueue()
+	for _, clientName := range snapshot.Queue {
+		client, ok := clients[clientName]
+		if !ok {
+			return ErrInvalidSnapshot
+		}
+		clientQueue.Push(&client, c.memberships[clientName])
+	}
+	clientQueue.frontSize = snapshot.QueueFrontSize
+
+	for _, clientName := range snapshot.WaitingArea {
+		if _, ok := clients[clientName]; !ok {
+			return ErrInvalidSnapshot
+		}
+	}
+
+	for _, reservation := range snapshot.Reservations {
+		if _, ok := tables[reservation.TableId]; !ok {
+			return ErrInvalidSnapshot
+		}
+	}
+
+	outcomes := make(Outcomes, 0, max(len(snapshot.Outcomes), startOutcomesSize))
+	for _, snapshotOutcome := range snapshot.Outcomes {
+		outcome := snapshotOutcome.Outcome
+
+		if snapshotOutcome.Err != "" {
+			i := slices.IndexFunc(outcomeErrors, func(err error) bool {
+				return err.Error() == snapshotOutcome.Err
+			})
+			if i < 0 {
+				return ErrInvalidSnapshot
+			}
+			outcome.Err = outcomeErrors[i]
+		}
+
+		outcomes = append(outcomes, outcome)
+	}
+
+	c.openingTime = snapshot.OpeningTime
+	c.closingTime = snapshot.ClosingTime
+	c.tables = tables
+	c.clients = clients
+	c.clientQueue = clientQueue
+	c.waitingArea = slices.Clone(snapshot.WaitingArea)
+	c.waitStats = snapshot.WaitStats
+	c.queueStats = snapshot.QueueStats
+	c.reservations = slices.Clone(snapshot.Reservations)
+	c.noShows = slices.Clone(snapshot.NoShows)
+	c.outcomes = outcomes
+	c.periodTables = periodTables
+	c.periodStartDate = snapshot.PeriodStartDate
+	c.periodEndDate = snapshot.PeriodEndDate
+
+	return nil
+}
+
+func sortedTables(tables map[TableId]Table) []Table {
+	sorted := make([]Table, 0, len(tables))
+	for _, table := range tables {
+		table.PlayedParts = slices.Clone(table.PlayedParts)
+		sorted = append(sorted, table)
+	}
+
+	slices.SortFunc(sorted, func(a, b Table) int {
+		return int(a.Id - b.Id)
+	})
+
+	return sorted
+}
@@ -0,0 +1,148 @@
+package computerclub
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// TableOccupancy is the state of a table at the moment. ClientName and
+// BusySince are set for a busy table only, a table out of service is not busy.
+// A table kept for a paused client is busy and paused, but has no BusySince.
+type TableOccupancy struct {
+	TableId        TableId
+	Category       string
+	IsBusy         bool
+	IsOutOfService bool
+	IsPaused       bool
+	ClientName     ClientName
+	BusySince      time.Time
+}
+
+// QueuedClient is a waiting client, Position 1 is the next to take a table.
+type QueuedClient struct {
+	Position   int
+	ClientName ClientName
+}
+
+// GetOccupancy returns the state of every table at the moment, ordered by id.
+func (c *computerClubServiceImpl) GetOccupancy() []TableOccupancy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	occupancy := make([]TableOccupancy, 0, c.tablesCount)
+
+	for tableId := TableId(minTablesCount); tableId <= TableId(c.tablesCount); tableId++ {
+		table := c.tables[tableId]
+
+		tableOccupancy := TableOccupancy{
+			TableId:        tableId,
+			Category:       table.Category,
+			IsBusy:         table.State == StateTableIsBusy || table.State == StateTableIsPaused,
+			IsOutOfService: table.State == StateTableIsOutOfService,
+			IsPaused:       table.State == StateTableIsPaused,
+		}
+		if table.State == StateTableIsBusy {
+			tableOccupancy.BusySince = table.StartTime
+		}
+
+		occupancy = append(occupancy, tableOccupancy)
+	}
+
+	for _, client := range c.clients {
+		if client.State == StateClientTookPlace || client.State == StateClientIsPaused {
+			occupancy[client.BusyTableId-minTablesCount].ClientName = client.Name
+		}
+	}
+
+	return occupancy
+}
+
+// GetClients returns the clients in the club at the moment, ordered by name.
+func (c *computerClubServiceImpl) GetClients() []Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clients := make([]Client, 0, len(c.clients))
+
+	for _, client := range c.clients {
+		clients = append(clients, client)
+	}
+
+	slices.SortFunc(clients, func(a, b Client) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	})
+
+	return clients
+}
+
+// GetQueue returns the waiting clients in the order they take tables at the
+// given time.
+func (c *computerClubServiceImpl) GetQueue(at time.Time) []QueuedClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clientNames := c.clientQueue.OrderedClientNames(at)
+
+	queue := make([]QueuedClient, 0, len(clientNames))
+	for i, clientName := range clientNames {
+		queue = append(queue, QueuedClient{
+			Position:   i + 1,
+			ClientName: clientName,
+		})
+	}
+
+	return queue
+}
+
+// GetRunningRevenue returns the revenue of the day as if the busy and paused
+// tables were freed at the given time.
+func (c *computerClubServiceImpl) GetRunningRevenue(at time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var revenue int
+
+	for _, table := range c.tables {
+		revenue += table.Profit
+
+		session, ok := runningSession(&table, at)
+		if !ok {
+			continue
+		}
+
+		session.calculateProfit(c.tariffSchedules[table.Category], c.billingPolicy)
+
+		revenue += session.Profit
+	}
+
+	return revenue
+}
+
+// runningSession returns the session at the table as if it ended at the given
+// time, the session of the paused table ended with the pause.
+func runningSession(table *Table, at time.Time) (Table, bool) {
+	switch table.State {
+	case StateTableIsBusy:
+		if !at.After(table.StartTime) && len(table.PlayedParts) == 0 {
+			return Table{}, false
+		}
+
+		endTime := at
+		if endTime.Before(table.StartTime) {
+			endTime = table.StartTime
+		}
+
+		return Table{StartTime: table.StartTime, EndTime: endTime, PlayedParts: table.PlayedParts}, true
+	case StateTableIsPaused:
+		lastPlayedPart := table.PlayedParts[len(table.PlayedParts)-1]
+
+		return Table{
+			StartTime:   lastPlayedPart.StartTime,
+			EndTime:     lastPlayedPart.EndTime,
+			PlayedParts: table.PlayedParts[:len(table.PlayedParts)-1],
+		}, true
+	default:
+		return Table{}, false
+	}
+}
@@ -2,9 +2,12 @@ package computerclub
 
 import (
 	"fmt"
+	"slices"
 	"time"
 )
 
+const minutesPerHour = 60
+
 type TableId int
 
 func (tableId *TableId) Int() int {
@@ -13,38 +16,71 @@ func (tableId *TableId) Int() int {
 
 type Table struct {
 	Id              TableId
+	Category        string
 	State           uint8
 	Profit          int
 	StartTime       time.Time
 	EndTime         time.Time
 	UsageTimePerDay time.Duration
+
+	// OutOfServiceSince is set, while the table is out of service
+	OutOfServiceSince time.Time
+	DowntimePerDay    time.Duration
+
+	// PlayedParts are the parts of the session before its pauses, the session
+	// is billed as a whole, once the table is freed
+	PlayedParts []SessionPart
 }
 
-func (t *Table) calculateProfit(pricePerHour int) {
-	if t.EndTime.Before(t.StartTime) {
-		t.EndTime = t.EndTime.Add(24 * time.Hour)
-	}
+// SessionPart is a part of a table session between pauses.
+type SessionPart struct {
+	StartTime time.Time
+	EndTime   time.Time
+}
 
-	usageTime := t.EndTime.Sub(t.StartTime)
+// sessionParts returns the parts of the session, that ends at EndTime.
+func (t *Table) sessionParts() []SessionPart {
+	return append(slices.Clone(t.PlayedParts), SessionPart{StartTime: t.StartTime, EndTime: t.EndTime})
+}
 
-	hours := int(usageTime.Hours())
-	minutes := int(usageTime.Minutes()) % 60
+// calculateProfit bills the session, the billing policy applies once to the
+// usage time of all its parts. The usage time is split by the tariffs first, the
+// time billed over it is priced as the usage time on average, so that a tariff,
+// that the client has not used, is never billed.
+func (t *Table) calculateProfit(schedule *tariffSchedule, billingPolicy BillingPolicy) {
+	var usageMinutes, usedMinutesCost int
+	for _, sessionPart := range t.sessionParts() {
+		for _, segment := range schedule.split(sessionPart.StartTime, sessionPart.EndTime) {
+			segmentMinutes := int(segment.endTime.Sub(segment.startTime).Minutes())
+			usageMinutes += segmentMinutes
+			usedMinutesCost += segmentMinutes * segment.pricePerHour
+		}
+	}
 
-	t.Profit += hours * pricePerHour
-	if minutes > 0 {
-		t.Profit += pricePerHour
+	if usageMinutes == 0 {
+		return
 	}
+
+	billableMinutes := int(billingPolicy.BillableTime(time.Duration(usageMinutes) * time.Minute).Minutes())
+	billableCost := usedMinutesCost * billableMinutes
+	usageCost := usageMinutes * minutesPerHour
+
+	// round up, so that a started minute is never billed for free
+	t.Profit += (billableCost + usageCost - 1) / usageCost
 }
 
 func (t *Table) calculateUsageTime() {
-	if t.EndTime.Before(t.StartTime) {
-		t.EndTime = t.EndTime.Add(24 * time.Hour)
+	for _, sessionPart := range t.sessionParts() {
+		t.UsageTimePerDay += sessionPart.EndTime.Sub(sessionPart.StartTime)
 	}
-	t.UsageTimePerDay += t.EndTime.Sub(t.StartTime)
 }
 
 func (t *Table) usageTimePerDayString() string {
-	hours := int(t.UsageTimePerDay.Hours())
-	minutes := int(t.UsageTimePerDay.Minutes()) % 60
+	return formatUsageTime(t.UsageTimePerDay)
+}
+
+func formatUsageTime(usageTime time.Duration) string {
+	hours := int(usageTime.Hours())
+	minutes := int(usageTime.Minutes()) % 60
 	return fmt.Sprintf("%02d:%02d", hours, minutes)
 }
@@ -0,0 +1,11 @@
+package computerclub
+
+// StandardTableCategory is the category of every table not listed in Config.Categories.
+const StandardTableCategory = "standard"
+
+// TableCategory groups tables that share a price per hour.
+type TableCategory struct {
+	Name         string
+	PricePerHour int
+	TableIds     []TableId
+}
@@ -3,4 +3,6 @@ package computerclub
 const (
 	StateTableIsBusy uint8 = iota
 	StateTableIsFree
+	StateTableIsOutOfService
+	StateTableIsPaused
 )
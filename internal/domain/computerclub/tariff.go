@@ -0,0 +1,102 @@
+package computerclub
+
+import "time"
+
+const minutesPerDay = 24 * minutesPerHour
+
+// Tariff overrides the price per hour between StartTime and EndTime. A tariff
+// whose EndTime is not after its StartTime lasts past midnight. Category names
+// the tables the tariff applies to, an empty one stands for the standard tables.
+type Tariff struct {
+	StartTime    time.Time
+	EndTime      time.Time
+	PricePerHour int
+	Category     string
+}
+
+func (t *Tariff) contains(minuteOfDay int) bool {
+	startMinute := minuteOfDayOf(t.StartTime)
+	endMinute := minuteOfDayOf(t.EndTime)
+
+	if startMinute < endMinute {
+		return minuteOfDay >= startMinute && minuteOfDay < endMinute
+	}
+
+	return minuteOfDay >= startMinute || minuteOfDay < endMinute
+}
+
+// tariffSegment is a part of a table session billed at a single price.
+type tariffSegment struct {
+	startTime    time.Time
+	endTime      time.Time
+	pricePerHour int
+}
+
+// tariffSchedule prices table usage by the time of day. The base price per hour
+// applies whenever none of the tariffs does.
+type tariffSchedule struct {
+	basePricePerHour int
+	tariffs          []Tariff
+}
+
+func newTariffSchedule(basePricePerHour int, tariffs []Tariff) *tariffSchedule {
+	return &tariffSchedule{
+		basePricePerHour: basePricePerHour,
+		tariffs:          tariffs,
+	}
+}
+
+func (s *tariffSchedule) priceAt(t time.Time) int {
+	minuteOfDay := minuteOfDayOf(t)
+
+	for _, tariff := range s.tariffs {
+		if tariff.contains(minuteOfDay) {
+			return tariff.PricePerHour
+		}
+	}
+
+	return s.basePricePerHour
+}
+
+// split cuts the period between startTime and endTime at every tariff boundary.
+func (s *tariffSchedule) split(startTime time.Time, endTime time.Time) []tariffSegment {
+	var segments []tariffSegment
+
+	for segmentStart := startTime; segmentStart.Before(endTime); {
+		segmentEnd := s.nextBoundary(segmentStart)
+		if segmentEnd.After(endTime) {
+			segmentEnd = endTime
+		}
+
+		segments = append(segments, tariffSegment{
+			startTime:    segmentStart,
+			endTime:      segmentEnd,
+			pricePerHour: s.priceAt(segmentStart),
+		})
+
+		segmentStart = segmentEnd
+	}
+
+	return segments
+}
+
+// nextBoundary returns the nearest moment after t when a tariff starts or ends.
+func (s *tariffSchedule) nextBoundary(t time.Time) time.Time {
+	minuteOfDay := minuteOfDayOf(t)
+	minutesToBoundary := minutesPerDay
+
+	for _, tariff := range s.tariffs {
+		for _, boundary := range []time.Time{tariff.StartTime, tariff.EndTime} {
+			minutes := (minuteOfDayOf(boundary) - minuteOfDay + minutesPerDay) % minutesPerDay
+			if minutes > 0 && minutes < minutesToBoundary {
+				minutesToBoundary = minutes
+			}
+		}
+	}
+
+	return t.Truncate(time.Minute).Add(time.Duration(minutesToBoundary) * time.Minute)
+}
+
+func minuteOfDayOf(t time.Time) int {
+	return t.Hour()*minutesPerHour + t.Minute()
+}
@@ -0,0 +1,84 @@
+package computerclub
+
+import "time"
+
+// WaitStats sum up the waits of the day. A wait ends, once the client takes a
+// table, leaves, gives up or the club closes.
+type WaitStats struct {
+	// Clients is the number of the finished waits, including the given up ones
+	Clients   int
+	GaveUp    int
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+func (w *WaitStats) add(wait time.Duration) {
+	w.Clients++
+	w.TotalWait += wait
+	w.MaxWait = max(w.MaxWait, wait)
+}
+
+// AverageWait returns the average wait, it is zero, if nobody has waited.
+func (w *WaitStats) AverageWait() time.Duration {
+	if w.Clients == 0 {
+		return 0
+	}
+	return w.TotalWait / time.Duration(w.Clients)
+}
+
+// nextExpiredWait returns the waiting client, whose wait runs out first by the
+// given time. The clients in the waiting area give up as well.
+func (c *computerClubServiceImpl) nextExpiredWait(t time.Time) (*Client, bool) {
+	if c.maxWaitDuration == 0 {
+		return nil, false
+	}
+
+	var next *Client
+
+	for _, client := range c.clients {
+		if client.WaitingSince.IsZero() || c.waitEndTime(&client).After(t) {
+			continue
+		}
+
+		if next == nil || client.WaitingSince.Before(next.WaitingSince) ||
+			client.WaitingSince.Equal(next.WaitingSince) && client.Name < next.Name {
+			next = &client
+		}
+	}
+
+	return next, next != nil
+}
+
+func (c *computerClubServiceImpl) waitEndTime(client *Client) time.Time {
+	return client.WaitingSince.Add(c.maxWaitDuration)
+}
+
+// expireWait makes the client, whose wait has run out, leave the club.
+func (c *computerClubServiceImpl) expireWait(client *Client) {
+	waitEndTime := c.waitEndTime(client)
+
+	c.stopWaiting(client, waitEndTime)
+	c.waitStats.GaveUp++
+
+	if client.State == StateClientIsInWaitingArea {
+		c.removeFromWaitingArea(client.Name)
+	} else {
+		c.clientQueue.Remove(client.Name)
+	}
+
+	c.deleteClient(client.Name)
+
+	c.outcomes.addOutgoingEvent(waitEndTime, OutgoingEventClientLeft, client.Name, 0)
+
+	c.fillQueueFromWaitingArea(waitEndTime)
+}
+
+// stopWaiting ends the wait of the client, if the client is waiting.
+func (c *computerClubServiceImpl) stopWaiting(client *Client, t time.Time) {
+	if client.WaitingSince.IsZero() {
+		return
+	}
+
+	c.waitStats.add(t.Sub(client.WaitingSince))
+	client.WaitingSince = time.Time{}
+}
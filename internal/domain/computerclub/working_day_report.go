@@ -2,13 +2,64 @@ package computerclub
 
 import (
 	"fmt"
+	"github.com/vaberof/yadro-test-task/pkg/xtime"
+	"strings"
 	"time"
 )
 
 type WorkingDayReport []byte
 
+const startBufSize = 512
+
 const layoutHoursMinutes = "15:04"
 
+// NewWorkingDayReport renders the outcomes in the text format of the report.
+func NewWorkingDayReport(outcomes []Outcome) WorkingDayReport {
+	workingDayReport := make(WorkingDayReport, 0, startBufSize)
+
+	for _, outcome := range outcomes {
+		workingDayReport.writeOutcome(outcome)
+	}
+
+	return workingDayReport
+}
+
+func (w *WorkingDayReport) writeOutcome(outcome Outcome) {
+	switch outcome.Kind {
+	case OutcomeKindOpening, OutcomeKindClosing:
+		w.writeTime(outcome.Time)
+	case OutcomeKindIncomingEvent, OutcomeKindOutgoingEvent:
+		if outcome.Err != nil {
+			w.writeEventError(outcome.Time, outcome.Err)
+		} else if outcome.EventType == IncomingEventClientReserved && outcome.Kind == OutcomeKindIncomingEvent {
+			w.writeReservationEvent(outcome.Time, outcome.ClientName, outcome.TableId, outcome.ReservationStart, outcome.ReservationEnd)
+		} else if outcome.TableId != 0 {
+			w.writeEventWithTableId(outcome.Time, outcome.EventType, outcome.ClientName, outcome.TableId)
+		} else {
+			w.writeEvent(outcome.Time, outcome.EventType, outcome.ClientName)
+		}
+	case OutcomeKindTableSummary:
+		if outcome.Category != "" {
+			w.writeTableReportWithCategory(outcome.TableId, outcome.Profit, formatUsageTime(outcome.UsageTime), outcome.Category)
+		} else {
+			w.writeTableReport(outcome.TableId, outcome.Profit, formatUsageTime(outcome.UsageTime))
+		}
+		if outcome.Downtime > 0 {
+			w.writeDowntime(outcome.TableId, formatUsageTime(outcome.Downtime))
+		}
+	case OutcomeKindNoShow:
+		w.writeNoShow(outcome.TableId, outcome.ClientName, outcome.ReservationStart, outcome.ReservationEnd)
+	case OutcomeKindCategorySummary:
+		w.writeCategoryReport(outcome.Category, outcome.Profit)
+	case OutcomeKindWaitSummary:
+		w.writeWaitSummary(outcome.WaitStats)
+	case OutcomeKindQueueAnalytics:
+		w.writeQueueAnalytics(outcome.WaitStats, outcome.QueueStats)
+	case OutcomeKindPeriod:
+		w.writePeriod(outcome.StartDate, outcome.EndDate)
+	}
+}
+
 func (w *WorkingDayReport) writeEvent(eventTime time.Time, eventType uint8, clientName ClientName) {
 	*w = append(*w, []byte(w.buildEvent(eventTime, eventType, clientName))...)
 }
@@ -17,6 +68,10 @@ func (w *WorkingDayReport) writeEventWithTableId(eventTime time.Time, eventType
 	*w = append(*w, []byte(w.buildEventWithTableId(eventTime, eventType, clientName, tableId))...)
 }
 
+func (w *WorkingDayReport) writeReservationEvent(eventTime time.Time, clientName ClientName, tableId TableId, startTime time.Time, endTime time.Time) {
+	*w = append(*w, []byte(w.buildReservationEvent(eventTime, clientName, tableId, startTime, endTime))...)
+}
+
 func (w *WorkingDayReport) writeEventError(eventTime time.Time, err error) {
 	*w = append(*w, []byte(w.buildEventError(eventTime, err))...)
 }
@@ -29,6 +84,34 @@ func (w *WorkingDayReport) writeTableReport(tableId TableId, profit int, usageTi
 	*w = append(*w, []byte(w.buildTableReport(tableId, profit, usageTimeStr))...)
 }
 
+func (w *WorkingDayReport) writeTableReportWithCategory(tableId TableId, profit int, usageTimeStr string, category string) {
+	*w = append(*w, []byte(w.buildTableReportWithCategory(tableId, profit, usageTimeStr, category))...)
+}
+
+func (w *WorkingDayReport) writeDowntime(tableId TableId, downtimeStr string) {
+	*w = append(*w, []byte(w.buildDowntime(tableId, downtimeStr))...)
+}
+
+func (w *WorkingDayReport) writeNoShow(tableId TableId, clientName ClientName, startTime time.Time, endTime time.Time) {
+	*w = append(*w, []byte(w.buildNoShow(tableId, clientName, startTime, endTime))...)
+}
+
+func (w *WorkingDayReport) writeCategoryReport(category string, profit int) {
+	*w = append(*w, []byte(w.buildCategoryReport(category, profit))...)
+}
+
+func (w *WorkingDayReport) writeWaitSummary(waitStats WaitStats) {
+	*w = append(*w, []byte(w.buildWaitSummary(waitStats))...)
+}
+
+func (w *WorkingDayReport) writeQueueAnalytics(waitStats WaitStats, queueStats QueueStats) {
+	*w = append(*w, []byte(w.buildQueueAnalytics(waitStats, queueStats))...)
+}
+
+func (w *WorkingDayReport) writePeriod(startDate time.Time, endDate time.Time) {
+	*w = append(*w, []byte(w.buildPeriod(startDate, endDate))...)
+}
+
 func (w *WorkingDayReport) buildEvent(eventTime time.Time, eventType uint8, clientName ClientName) string {
 	return fmt.Sprintf("%s %d %s\n", eventTime.Format(layoutHoursMinutes), eventType, clientName.String())
 }
@@ -37,6 +120,11 @@ func (w *WorkingDayReport) buildEventWithTableId(eventTime time.Time, eventType
 	return fmt.Sprintf("%s %d %s %d\n", eventTime.Format(layoutHoursMinutes), eventType, clientName.String(), tableId.Int())
 }
 
+func (w *WorkingDayReport) buildReservationEvent(eventTime time.Time, clientName ClientName, tableId TableId, startTime time.Time, endTime time.Time) string {
+	return fmt.Sprintf("%s %d %s %d %s %s\n", eventTime.Format(layoutHoursMinutes), IncomingEventClientReserved, clientName.String(), tableId.Int(),
+		startTime.Format(layoutHoursMinutes), endTime.Format(layoutHoursMinutes))
+}
+
 func (w *WorkingDayReport) buildEventError(eventTime time.Time, err error) string {
 	return fmt.Sprintf("%s %d %s\n", eventTime.Format(layoutHoursMinutes), OutgoingEventError, err.Error())
 }
@@ -48,3 +136,50 @@ func (w *WorkingDayReport) buildTime(time time.Time) string {
 func (w *WorkingDayReport) buildTableReport(tableId TableId, profit int, usageTime string) string {
 	return fmt.Sprintf("%d %d %s\n", tableId.Int(), profit, usageTime)
 }
+
+func (w *WorkingDayReport) buildTableReportWithCategory(tableId TableId, profit int, usageTime string, category string) string {
+	return fmt.Sprintf("%d %d %s %s\n", tableId.Int(), profit, usageTime, category)
+}
+
+func (w *WorkingDayReport) buildDowntime(tableId TableId, downtime string) string {
+	return fmt.Sprintf("%d downtime %s\n", tableId.Int(), downtime)
+}
+
+func (w *WorkingDayReport) buildNoShow(tableId TableId, clientName ClientName, startTime time.Time, endTime time.Time) string {
+	return fmt.Sprintf("%d no-show %s %s %s\n", tableId.Int(), clientName.String(), startTime.Format(layoutHoursMinutes), endTime.Format(layoutHoursMinutes))
+}
+
+func (w *WorkingDayReport) buildCategoryReport(category string, profit int) string {
+	return fmt.Sprintf("%s %d\n", category, profit)
+}
+
+func (w *WorkingDayReport) buildWaitSummary(waitStats WaitStats) string {
+	return fmt.Sprintf("wait %d %s %s %d\n", waitStats.Clients, formatUsageTime(waitStats.AverageWait()), formatUsageTime(waitStats.MaxWait), waitStats.GaveUp)
+}
+
+// buildQueueAnalytics renders the analytics section: its name followed by a
+// line per figure.
+func (w *WorkingDayReport) buildQueueAnalytics(waitStats WaitStats, queueStats QueueStats) string {
+	var builder strings.Builder
+
+	builder.WriteString("analytics\n")
+	fmt.Fprintf(&builder, "waited %d\n", waitStats.Clients)
+	fmt.Fprintf(&builder, "average-wait %s\n", formatUsageTime(waitStats.AverageWait()))
+	fmt.Fprintf(&builder, "max-wait %s\n", formatUsageTime(waitStats.MaxWait))
+	fmt.Fprintf(&builder, "gave-up %d\n", waitStats.GaveUp)
+	fmt.Fprintf(&builder, "turned-away %d\n", queueStats.TurnedAway)
+	fmt.Fprintf(&builder, "waiting-area %d\n", queueStats.SentToWaitingArea)
+	fmt.Fprintf(&builder, "rejected %d\n", queueStats.Rejected)
+
+	if queueStats.PeakLength > 0 {
+		fmt.Fprintf(&builder, "peak-queue %d %s\n", queueStats.PeakLength, queueStats.PeakTime.Format(layoutHoursMinutes))
+	} else {
+		builder.WriteString("peak-queue 0\n")
+	}
+
+	return builder.String()
+}
+
+func (w *WorkingDayReport) buildPeriod(startDate time.Time, endDate time.Time) string {
+	return fmt.Sprintf("%s %s\n", xtime.FormatDate(startDate), xtime.FormatDate(endDate))
+}
@@ -2,7 +2,10 @@ package xtime
 
 import "time"
 
-const layoutHoursMinutes = "15:04"
+const (
+	layoutHoursMinutes = "15:04"
+	layoutDate         = "2006-01-02"
+)
 
 func ParseHoursMinutesFromString(strTime string) (time.Time, error) {
 	t, err := time.Parse(layoutHoursMinutes, strTime)
@@ -11,3 +14,34 @@ func ParseHoursMinutesFromString(strTime string) (time.Time, error) {
 	}
 	return t, nil
 }
+
+func ParseDateFromString(strDate string) (time.Time, error) {
+	t, err := time.Parse(layoutDate, strDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+func FormatDate(date time.Time) string {
+	return date.Format(layoutDate)
+}
+
+// OnDate returns the time of day of clock on the given date.
+func OnDate(date time.Time, clock time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, date.Location())
+}
+
+// StartOfDay returns the midnight of the given time.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// NotBefore returns the first time of day of clock, that is not before t.
+func NotBefore(t time.Time, clock time.Time) time.Time {
+	next := OnDate(t, clock)
+	if next.Before(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}